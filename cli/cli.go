@@ -0,0 +1,178 @@
+// Package cli parses the binary's subcommands and produces the output of those that only read the game's state.
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rytrose/soup-the-moon/game/flow"
+	"github.com/rytrose/soup-the-moon/game/state"
+)
+
+// Exit codes.
+const (
+	ExitOK    = 0 // ExitOK is returned when a command succeeds.
+	ExitError = 1 // ExitError is returned when a command fails.
+	ExitUsage = 2 // ExitUsage is returned when a command is invoked incorrectly.
+)
+
+// Env is what commands run against.
+type Env struct {
+	Program  string           // Program is the name of the binary shown in usage.
+	Stdout   io.Writer        // Stdout receives command output.
+	Stderr   io.Writer        // Stderr receives usage and errors.
+	Now      func() time.Time // Now is the time leaderboard scopes are relative to.
+	RunGame  func()           // RunGame runs the game.
+	RPIOTest func()           // RPIOTest prints edges detected on the planet pins for a minute.
+}
+
+// command is a subcommand of the binary.
+type command struct {
+	name    string                            // name is how the command is invoked.
+	summary string                            // summary is a one line description shown in usage.
+	run     func(env *Env, args []string) int // run runs the command with its arguments, returning an exit code.
+}
+
+// commands are the available subcommands, the first being the default when none is given.
+var commands []*command
+
+func init() {
+	// Set in init as commands print usage, which lists the commands
+	commands = []*command{
+		{name: "run", summary: "run the game (default)", run: runGame},
+		{name: "dump", summary: "print the saved state and leaderboard", run: runDump},
+		{name: "screens", summary: "print the screen state machine as a graph", run: runScreens},
+		{name: "rpio-test", summary: "print edges detected on the planet pins for a minute", run: runRPIOTest},
+	}
+}
+
+// Dispatch runs the subcommand named by the first argument, defaulting to run, and returns its exit code.
+func Dispatch(env *Env, args []string) int {
+	if len(args) == 0 || (len(args[0]) > 0 && args[0][0] == '-') {
+		// No subcommand, e.g. when started by an existing service unit
+		return commands[0].run(env, args)
+	}
+
+	if args[0] == "help" {
+		usage(env)
+		return ExitOK
+	}
+
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run(env, args[1:])
+		}
+	}
+
+	fmt.Fprintf(env.Stderr, "unknown command: %s\n", args[0])
+	usage(env)
+	return ExitUsage
+}
+
+// usage prints the available subcommands.
+func usage(env *Env) {
+	fmt.Fprintf(env.Stderr, "usage: %s [command] [flags]\n\ncommands:\n", env.Program)
+	w := tabwriter.NewWriter(env.Stderr, 0, 8, 2, ' ', 0)
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %s\t%s\n", c.name, c.summary)
+	}
+	w.Flush()
+}
+
+// parse parses a command's flags, returning false along with the exit code to stop with if the command shouldn't run.
+// Asking for help lists the flags and the subcommands, and succeeds.
+func parse(env *Env, flags *flag.FlagSet, args []string) (int, bool) {
+	flags.SetOutput(env.Stderr)
+	err := flags.Parse(args)
+	if err == flag.ErrHelp {
+		fmt.Fprintln(env.Stderr)
+		usage(env)
+		return ExitOK, false
+	}
+	if err != nil {
+		return ExitUsage, false
+	}
+
+	return ExitOK, true
+}
+
+// runGame runs the game.
+func runGame(env *Env, args []string) int {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	if code, ok := parse(env, flags, args); !ok {
+		return code
+	}
+
+	env.RunGame()
+	return ExitOK
+}
+
+// runDump prints the saved state and leaderboard.
+func runDump(env *Env, args []string) int {
+	flags := flag.NewFlagSet("dump", flag.ContinueOnError)
+	scopeName := flags.String("scope", "all", "leaderboard scope to print: all, daily or weekly")
+	if code, ok := parse(env, flags, args); !ok {
+		return code
+	}
+
+	scopes := map[string]state.LeaderboardScope{
+		"all":    state.ScopeAllTime,
+		"daily":  state.ScopeDaily,
+		"weekly": state.ScopeWeekly,
+	}
+	scope, exists := scopes[*scopeName]
+	if !exists {
+		fmt.Fprintf(env.Stderr, "unknown scope: %s\n", *scopeName)
+		return ExitUsage
+	}
+
+	fmt.Fprintf(env.Stdout, "initials: %s\n", state.FormatInitials(state.Global.CurrentInitials))
+	fmt.Fprintf(env.Stdout, "mode: %s\n\n", state.Global.CurrentMode)
+
+	w := tabwriter.NewWriter(env.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tINITIALS\tSCORE\tMODE\tTIME")
+	for i, entry := range state.ScopedEntries(scope, env.Now()) {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%s\t%s\n", i+1, state.FormatInitials(entry.Initials), entry.Score, entry.Mode, entry.Timestamp.Format(time.RFC3339))
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(env.Stderr, "unable to print leaderboard: %s\n", err)
+		return ExitError
+	}
+
+	return ExitOK
+}
+
+// runScreens prints the screen state machine as a graph.
+func runScreens(env *Env, args []string) int {
+	flags := flag.NewFlagSet("screens", flag.ContinueOnError)
+	format := flags.String("format", "dot", "graph format: dot or mermaid")
+	if code, ok := parse(env, flags, args); !ok {
+		return code
+	}
+
+	switch *format {
+	case "dot":
+		fmt.Fprint(env.Stdout, flow.DOT())
+	case "mermaid":
+		fmt.Fprint(env.Stdout, flow.Mermaid())
+	default:
+		fmt.Fprintf(env.Stderr, "unknown format: %s\n", *format)
+		return ExitUsage
+	}
+
+	return ExitOK
+}
+
+// runRPIOTest prints edges detected on the planet pins for a minute.
+func runRPIOTest(env *Env, args []string) int {
+	flags := flag.NewFlagSet("rpio-test", flag.ContinueOnError)
+	if code, ok := parse(env, flags, args); !ok {
+		return code
+	}
+
+	env.RPIOTest()
+	return ExitOK
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rytrose/soup-the-moon/game/flow"
+	"github.com/rytrose/soup-the-moon/game/state"
+)
+
+// testNow is the time commands run at, a Wednesday afternoon.
+var testNow = time.Date(2026, time.October, 14, 15, 0, 0, 0, time.Local)
+
+// testEnv is an Env recording output and which of the hardware commands ran.
+type testEnv struct {
+	Env
+	stdout  bytes.Buffer
+	stderr  bytes.Buffer
+	ranGame bool
+	ranRPIO bool
+}
+
+// newTestEnv is a testEnv factory.
+func newTestEnv() *testEnv {
+	e := &testEnv{}
+	e.Env = Env{
+		Program:  "skeeball",
+		Stdout:   &e.stdout,
+		Stderr:   &e.stderr,
+		Now:      func() time.Time { return testNow },
+		RunGame:  func() { e.ranGame = true },
+		RPIOTest: func() { e.ranRPIO = true },
+	}
+	return e
+}
+
+// useTestState loads a state file with a few leaderboard entries for the duration of a test.
+func useTestState(t *testing.T) {
+	state.UseFile(filepath.Join(t.TempDir(), "state.gob"))
+
+	state.Global.CurrentInitials = []int{17, 24, 0}
+	state.Global.CurrentMode = "Cricket"
+	entries := []*state.LeaderboardEntry{
+		{Initials: []int{0, 1, 2}, Score: 900, Mode: "Classic", Timestamp: testNow.Add(-time.Hour)},
+		{Initials: []int{25, 26, 29}, Score: 12000, Mode: "Cricket", Timestamp: testNow.AddDate(0, 0, -30)},
+	}
+	for _, entry := range entries {
+		if err := state.AddLeaderboardEntry(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// listsCommands returns true if usage listing every command was written.
+func listsCommands(output string) bool {
+	for _, c := range commands {
+		if !strings.Contains(output, "  "+c.name+" ") {
+			return false
+		}
+	}
+	return strings.Contains(output, "usage: skeeball [command] [flags]")
+}
+
+func TestDispatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    int
+		ranGame bool
+		ranRPIO bool
+		usage   bool
+		stderr  string
+	}{
+		{name: "no command runs the game", args: nil, want: ExitOK, ranGame: true},
+		{name: "flags without a command go to run", args: []string{"-v=false"}, want: ExitUsage, stderr: "flag provided but not defined"},
+		{name: "run", args: []string{"run"}, want: ExitOK, ranGame: true},
+		{name: "rpio-test", args: []string{"rpio-test"}, want: ExitOK, ranRPIO: true},
+		{name: "help", args: []string{"help"}, want: ExitOK, usage: true},
+		{name: "-h lists commands", args: []string{"-h"}, want: ExitOK, usage: true},
+		{name: "command -h lists its flags and commands", args: []string{"dump", "-h"}, want: ExitOK, usage: true, stderr: "-scope"},
+		{name: "unknown command", args: []string{"replay"}, want: ExitUsage, usage: true, stderr: "unknown command: replay"},
+		{name: "unknown flag", args: []string{"screens", "-color"}, want: ExitUsage, stderr: "flag provided but not defined: -color"},
+		{name: "unknown format", args: []string{"screens", "-format", "png"}, want: ExitUsage, stderr: "unknown format: png"},
+		{name: "unknown scope", args: []string{"dump", "-scope", "yearly"}, want: ExitUsage, stderr: "unknown scope: yearly"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := newTestEnv()
+
+			if got := Dispatch(&e.Env, test.args); got != test.want {
+				t.Errorf("got exit code %d, want %d", got, test.want)
+			}
+			if e.ranGame != test.ranGame || e.ranRPIO != test.ranRPIO {
+				t.Errorf("got game run %t and rpio-test run %t, want %t and %t", e.ranGame, e.ranRPIO, test.ranGame, test.ranRPIO)
+			}
+			if got := listsCommands(e.stderr.String()); got != test.usage {
+				t.Errorf("got usage printed %t, want %t, in stderr %q", got, test.usage, e.stderr.String())
+			}
+			if !strings.Contains(e.stderr.String(), test.stderr) {
+				t.Errorf("got stderr %q, want it to contain %q", e.stderr.String(), test.stderr)
+			}
+		})
+	}
+}
+
+func TestScreens(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"screens"}, flow.DOT()},
+		{[]string{"screens", "-format", "dot"}, flow.DOT()},
+		{[]string{"screens", "-format", "mermaid"}, flow.Mermaid()},
+	}
+
+	for _, test := range tests {
+		e := newTestEnv()
+		if got := Dispatch(&e.Env, test.args); got != ExitOK {
+			t.Errorf("got exit code %d for %v, want %d", got, test.args, ExitOK)
+		}
+		if got := e.stdout.String(); got != test.want {
+			t.Errorf("got output %q for %v, want %q", got, test.args, test.want)
+		}
+	}
+
+	// Every screen and transition is in the graph
+	e := newTestEnv()
+	Dispatch(&e.Env, []string{"screens"})
+	if got := strings.Count(e.stdout.String(), " -> "); got != len(flow.Transitions) {
+		t.Errorf("got %d edges in the graph, want %d", got, len(flow.Transitions))
+	}
+	if !strings.Contains(e.stdout.String(), "\tInitials -> Scoring [label=\"initials entered\"];\n") {
+		t.Errorf("got graph %q, want the initials entered edge", e.stdout.String())
+	}
+}
+
+func TestDump(t *testing.T) {
+	useTestState(t)
+	recent := testNow.Add(-time.Hour).Format(time.RFC3339)
+	old := testNow.AddDate(0, 0, -30).Format(time.RFC3339)
+
+	tests := []struct {
+		scope string
+		want  []string
+	}{
+		{"all", []string{"1  Z ! *     12000  Cricket  " + old, "2  A B C     900    Classic  " + recent}},
+		{"daily", []string{"1  A B C     900    Classic  " + recent}},
+		{"weekly", []string{"1  A B C     900    Classic  " + recent}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scope, func(t *testing.T) {
+			e := newTestEnv()
+			if got := Dispatch(&e.Env, []string{"dump", "-scope", test.scope}); got != ExitOK {
+				t.Fatalf("got exit code %d, want %d with stderr %q", got, ExitOK, e.stderr.String())
+			}
+
+			lines := strings.Split(strings.TrimSuffix(e.stdout.String(), "\n"), "\n")
+			want := append([]string{"initials: R Y A", "mode: Cricket", "", "#  INITIALS  SCORE  MODE     TIME"}, test.want...)
+			if len(lines) != len(want) {
+				t.Fatalf("got output %q, want lines %q", lines, want)
+			}
+			for i := range want {
+				if strings.TrimRight(lines[i], " ") != want[i] {
+					t.Errorf("got line %q, want %q", lines[i], want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+// Package flow declares the screen state machine: its screens, the events screens emit and the transitions between them.
+package flow
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// ScreenID identifies the screen to be displayed.
+type ScreenID int
+
+// Enumeration of screens.
+const (
+	ScreenMenu ScreenID = iota
+	ScreenLeaderboard
+	ScreenInitials
+	ScreenScoring
+)
+
+// screenNames identify screens in logs, status and exported graphs.
+var screenNames = map[ScreenID]string{
+	ScreenMenu:        "Menu",
+	ScreenLeaderboard: "Leaderboard",
+	ScreenInitials:    "Initials",
+	ScreenScoring:     "Scoring",
+}
+
+// String returns the name of the screen.
+func (id ScreenID) String() string {
+	return screenNames[id]
+}
+
+// Event is an outcome of a screen update that may cause a transition.
+type Event int
+
+// Enumeration of events.
+const (
+	EventNone Event = iota
+	EventNewGame
+	EventLeaderboard
+	EventBack
+	EventInitialsEntered
+	EventQuit
+	EventGameOverContinued
+)
+
+// eventNames label events in logs and exported graphs.
+var eventNames = map[Event]string{
+	EventNone:              "none",
+	EventNewGame:           "new game selected",
+	EventLeaderboard:       "leaderboard selected",
+	EventBack:              "back",
+	EventInitialsEntered:   "initials entered",
+	EventQuit:              "quit confirmed",
+	EventGameOverContinued: "game over continued",
+}
+
+// String returns the name of the event.
+func (e Event) String() string {
+	return eventNames[e]
+}
+
+// Transition is a declared change from one screen to another when an event occurs.
+// Remaining on the same screen when no event occurs is always allowed and not declared.
+type Transition struct {
+	From ScreenID // From is the screen being left.
+	On   Event    // On is the event causing the transition.
+	To   ScreenID // To is the screen being entered.
+}
+
+// Transitions are all declared transitions of the screen state machine.
+var Transitions = []Transition{
+	{From: ScreenMenu, On: EventNewGame, To: ScreenInitials},
+	{From: ScreenMenu, On: EventLeaderboard, To: ScreenLeaderboard},
+	{From: ScreenLeaderboard, On: EventBack, To: ScreenMenu},
+	{From: ScreenInitials, On: EventBack, To: ScreenMenu},
+	{From: ScreenInitials, On: EventInitialsEntered, To: ScreenScoring},
+	{From: ScreenScoring, On: EventQuit, To: ScreenMenu},
+	{From: ScreenScoring, On: EventGameOverContinued, To: ScreenMenu},
+}
+
+// UndeclaredTransitionHook is called when an event occurs on a screen with no declared transition for it.
+// By default the event is logged and the screen is left unchanged.
+var UndeclaredTransitionHook = func(from ScreenID, event Event) {
+	log.Printf("undeclared screen transition: %s on %s", from, event)
+}
+
+// Next returns the screen to display after an event occurs on a screen, following the declared transitions.
+func Next(from ScreenID, event Event) ScreenID {
+	if event == EventNone {
+		return from
+	}
+
+	for _, transition := range Transitions {
+		if transition.From == from && transition.On == event {
+			return transition.To
+		}
+	}
+
+	UndeclaredTransitionHook(from, event)
+	return from
+}
+
+// DOT exports the screen state machine as a Graphviz DOT graph.
+func DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph screens {\n")
+	for _, id := range screenIDs() {
+		fmt.Fprintf(&b, "\t%s;\n", id)
+	}
+	for _, transition := range Transitions {
+		fmt.Fprintf(&b, "\t%s -> %s [label=%q];\n", transition.From, transition.To, transition.On)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid exports the screen state machine as a Mermaid state diagram.
+func Mermaid() string {
+	var b strings.Builder
+	b.WriteString("stateDiagram-v2\n")
+	fmt.Fprintf(&b, "\t[*] --> %s\n", ScreenMenu)
+	for _, transition := range Transitions {
+		fmt.Fprintf(&b, "\t%s --> %s: %s\n", transition.From, transition.To, transition.On)
+	}
+	return b.String()
+}
+
+// screenIDs returns all screen IDs in enumeration order.
+func screenIDs() []ScreenID {
+	return []ScreenID{
+		ScreenMenu,
+		ScreenLeaderboard,
+		ScreenInitials,
+		ScreenScoring,
+	}
+}
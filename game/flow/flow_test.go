@@ -0,0 +1,75 @@
+package flow
+
+import "testing"
+
+// recordUndeclared replaces UndeclaredTransitionHook for the duration of a test, recording the events it is called with.
+func recordUndeclared(t *testing.T) *[]Event {
+	undeclared := &[]Event{}
+	hook := UndeclaredTransitionHook
+	UndeclaredTransitionHook = func(from ScreenID, event Event) {
+		*undeclared = append(*undeclared, event)
+	}
+	t.Cleanup(func() { UndeclaredTransitionHook = hook })
+
+	return undeclared
+}
+
+func TestTransitionsDeterministic(t *testing.T) {
+	type key struct {
+		from  ScreenID
+		event Event
+	}
+
+	seen := map[key]bool{}
+	for _, transition := range Transitions {
+		k := key{transition.From, transition.On}
+		if seen[k] {
+			t.Errorf("got several transitions from %s on %s, want one", transition.From, transition.On)
+		}
+		seen[k] = true
+	}
+}
+
+func TestScreensReachable(t *testing.T) {
+	reached := map[ScreenID]bool{ScreenMenu: true}
+	for added := true; added; {
+		added = false
+		for _, transition := range Transitions {
+			if reached[transition.From] && !reached[transition.To] {
+				reached[transition.To] = true
+				added = true
+			}
+		}
+	}
+
+	for _, id := range screenIDs() {
+		if !reached[id] {
+			t.Errorf("screen %s is unreachable from %s", id, ScreenMenu)
+		}
+	}
+}
+
+func TestNextUndeclared(t *testing.T) {
+	tests := []struct {
+		from      ScreenID
+		event     Event
+		undeclare bool
+	}{
+		{ScreenMenu, EventNone, false},
+		{ScreenScoring, EventNone, false},
+		{ScreenMenu, EventBack, true},
+		{ScreenLeaderboard, EventNewGame, true},
+		{ScreenScoring, EventInitialsEntered, true},
+	}
+
+	for _, test := range tests {
+		undeclared := recordUndeclared(t)
+
+		if got := Next(test.from, test.event); got != test.from {
+			t.Errorf("got %s on %s entering %s, want it unchanged", test.from, test.event, got)
+		}
+		if got := len(*undeclared) == 1; got != test.undeclare {
+			t.Errorf("got undeclared transitions %v from %s on %s, want undeclared %t", *undeclared, test.from, test.event, test.undeclare)
+		}
+	}
+}
@@ -1,26 +1,38 @@
 package game
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"math/rand"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/rytrose/soup-the-moon/game/flow"
 	"github.com/rytrose/soup-the-moon/game/input"
 	"github.com/rytrose/soup-the-moon/game/screens"
+	"github.com/rytrose/soup-the-moon/game/state"
+	"github.com/rytrose/soup-the-moon/game/systemd"
 	"github.com/rytrose/soup-the-moon/game/util"
+	"github.com/rytrose/soup-the-moon/io"
 )
 
 // Game implements ebiten.Game and maintains state about the game.
 type Game struct {
-	w      int              // Screen size width.
-	h      int              // Screen size height.
-	c      uint64           // Frame counter
-	screen screens.ScreenID // An enumeration of the current screen being displayed.
+	w      int            // Screen size width.
+	h      int            // Screen size height.
+	c      uint64         // Frame counter
+	screen flow.ScreenID  // An enumeration of the current screen being displayed.
+	stop   chan os.Signal // Receives termination signals, ending the game loop.
 }
 
+// errTerminated ends the game loop when the process is asked to terminate.
+var errTerminated = errors.New("terminated")
+
 // Run starts the game.
 func Run() {
 	// Seed RNG
@@ -50,8 +62,21 @@ func Run() {
 		input.InitBack()
 	}
 
+	// End the game loop on termination, e.g. systemctl stop, so shutdown below still runs
+	g := newGame(640, 480)
+	signal.Notify(g.stop, syscall.SIGTERM, syscall.SIGINT)
+	defer signal.Stop(g.stop)
+
 	// Run game
-	if err := ebiten.RunGame(newGame(640, 480)); err != nil {
+	err := ebiten.RunGame(g)
+
+	// Tell systemd shutdown has begun
+	notify(systemd.Stopping())
+
+	// Write any deferred state before exiting
+	state.Flush()
+
+	if err != nil && err != errTerminated {
 		log.Fatal(err)
 	}
 }
@@ -59,32 +84,49 @@ func Run() {
 // newGame is a Game factory.
 func newGame(width, height int) *Game {
 	return &Game{
-		w: width,
-		h: height,
+		w:    width,
+		h:    height,
+		stop: make(chan os.Signal, 1),
 	}
 }
 
 // Update updates the game state.
 func (g *Game) Update() error {
+	// Stop once asked to terminate
+	select {
+	case sig := <-g.stop:
+		log.Printf("received %s, shutting down", sig)
+		return errTerminated
+	default:
+	}
+
 	// Increment frame counter
 	g.c++
 
+	// Tell systemd startup is complete once the game loop is running
+	if g.c == 1 {
+		notify(systemd.Ready())
+		notify(systemd.Status(g.screen.String()))
+	}
+
+	// Pat the systemd watchdog while the game loop and poller are healthy
+	notify(systemd.Watchdog(io.PollerStatus().Healthy))
+
 	// Update button states
 	if util.IsRasPi() {
 		input.RPIOButtonUpdate()
 	}
 
+	// Write deferred state saves that are due
+	state.Update()
+
 	// Screen state machine
-	var nextScreen screens.ScreenID
-	switch g.screen {
-	case screens.ScreenMenu:
-		nextScreen = screens.UpdateMenu()
-	case screens.ScreenInitials:
-		nextScreen = screens.UpdateInitials()
-	case screens.ScreenScoring:
-		nextScreen = screens.UpdateScoring(g.w, g.h)
-	case screens.ScreenLeaderboard:
-		nextScreen = screens.UpdateLeaderboard()
+	event := screens.Screens[g.screen].Update(g.w, g.h)
+	nextScreen := flow.Next(g.screen, event)
+
+	// Report screen changes to systemd
+	if nextScreen != g.screen {
+		notify(systemd.Status(nextScreen.String()))
 	}
 
 	// Set the next screen
@@ -93,21 +135,20 @@ func (g *Game) Update() error {
 	return nil
 }
 
+// notify logs a failed systemd notification.
+// Notifications are best effort and never stop the game.
+func notify(err error) {
+	if err != nil {
+		log.Printf("unable to notify systemd: %s", err)
+	}
+}
+
 // Draw draws a frame.
 func (g *Game) Draw(screen *ebiten.Image) {
 	ebitenutil.DebugPrint(screen, fmt.Sprintf("TPS: %0.2f", ebiten.CurrentTPS()))
 
 	// Draw appropriate screen
-	switch g.screen {
-	case screens.ScreenMenu:
-		screens.DrawMenu(g.c, g.w, g.h, screen)
-	case screens.ScreenInitials:
-		screens.DrawInitials(g.c, g.w, g.h, screen)
-	case screens.ScreenScoring:
-		screens.DrawScoring(g.c, g.w, g.h, screen)
-	case screens.ScreenLeaderboard:
-		screens.DrawLeaderboard(g.c, g.w, g.h, screen)
-	}
+	screens.Screens[g.screen].Draw(g.c, g.w, g.h, screen)
 }
 
 // Layout determines the game's layout.
@@ -0,0 +1,82 @@
+package i18n
+
+// Message IDs.
+const (
+	MenuNewGame       MessageID = "menu.new_game"
+	MenuMode          MessageID = "menu.mode"
+	MenuLeaderboard   MessageID = "menu.leaderboard"
+	ModeClassic       MessageID = "mode.classic"
+	ModeCricket       MessageID = "mode.cricket"
+	InitialsPrompt1   MessageID = "initials.prompt1"
+	InitialsPrompt2   MessageID = "initials.prompt2"
+	LeaderboardAll    MessageID = "leaderboard.all_time"
+	LeaderboardDaily  MessageID = "leaderboard.daily"
+	LeaderboardWeekly MessageID = "leaderboard.weekly"
+	ConfirmBackL1     MessageID = "scoring.confirm_back1"
+	ConfirmBackL2     MessageID = "scoring.confirm_back2"
+	ConfirmBackL3     MessageID = "scoring.confirm_back3"
+	ConfirmBackL4     MessageID = "scoring.confirm_back4"
+	GameOverL1        MessageID = "scoring.game_over1"
+	GameOverL2        MessageID = "scoring.game_over2"
+	GameOverL3        MessageID = "scoring.game_over3"
+	PlanetMercury     MessageID = "planet.mercury"
+	PlanetEarth       MessageID = "planet.earth"
+	PlanetMars        MessageID = "planet.mars"
+	PlanetJupiter     MessageID = "planet.jupiter"
+	PlanetSaturn      MessageID = "planet.saturn"
+	PlanetPluto       MessageID = "planet.pluto"
+)
+
+// catalogs contains the messages of every locale, keyed by locale then message ID.
+// Translations should fit the same screen space as the English message where possible.
+var catalogs = map[string]map[MessageID]string{
+	"en": {
+		MenuNewGame:       "New Game",
+		MenuMode:          "Mode: %s",
+		MenuLeaderboard:   "Leaderboard",
+		ModeClassic:       "Classic",
+		ModeCricket:       "Cricket",
+		InitialsPrompt1:   "Enter your",
+		InitialsPrompt2:   "initials...",
+		LeaderboardAll:    "Top Astronauts",
+		LeaderboardDaily:  "Today's Best",
+		LeaderboardWeekly: "This Week's Best",
+		ConfirmBackL1:     "Return to base?",
+		ConfirmBackL2:     "Press back to quit.",
+		ConfirmBackL3:     "Press forward",
+		ConfirmBackL4:     "to resume.",
+		GameOverL1:        "Game over!",
+		GameOverL2:        "Press forward",
+		GameOverL3:        "to continue.",
+		PlanetMercury:     "MERCURY",
+		PlanetEarth:       "EARTH",
+		PlanetMars:        "MARS",
+		PlanetJupiter:     "JUPITER",
+		PlanetSaturn:      "SATURN",
+		PlanetPluto:       "PLUTO",
+	},
+	"fr": {
+		MenuNewGame:       "Nouveau jeu",
+		MenuMode:          "Jeu: %s",
+		MenuLeaderboard:   "Classement",
+		ModeClassic:       "Classique",
+		InitialsPrompt1:   "Entrez vos",
+		InitialsPrompt2:   "initiales...",
+		LeaderboardAll:    "Top Astronautes",
+		LeaderboardDaily:  "Meilleurs du jour",
+		LeaderboardWeekly: "Meilleurs semaine",
+		ConfirmBackL1:     "Retour à la base ?",
+		ConfirmBackL2:     "Retour pour quitter.",
+		ConfirmBackL3:     "Avancer pour",
+		ConfirmBackL4:     "reprendre.",
+		GameOverL1:        "Partie terminée !",
+		GameOverL2:        "Avancer pour",
+		GameOverL3:        "continuer.",
+		PlanetMercury:     "MERCURE",
+		PlanetEarth:       "TERRE",
+		PlanetMars:        "MARS",
+		PlanetJupiter:     "JUPITER",
+		PlanetSaturn:      "SATURNE",
+		PlanetPluto:       "PLUTON",
+	},
+}
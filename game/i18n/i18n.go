@@ -0,0 +1,77 @@
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// MessageID identifies a translatable message.
+type MessageID string
+
+// DefaultLocale is the locale used when none is configured, and the fallback for missing translations.
+const DefaultLocale = "en"
+
+// locale is the current locale.
+var locale = DefaultLocale
+
+func init() {
+	// Configure the locale from the environment, e.g. LANG=fr_FR.UTF-8
+	SetLocale(os.Getenv("LANG"))
+}
+
+// SetLocale sets the current locale from a locale name such as "fr" or "fr_FR.UTF-8".
+// Unknown locales fall back to DefaultLocale.
+func SetLocale(name string) {
+	// Only the language is used
+	language := strings.ToLower(name)
+	if i := strings.IndexAny(language, "_.-@"); i >= 0 {
+		language = language[:i]
+	}
+
+	if _, exists := catalogs[language]; !exists {
+		language = DefaultLocale
+	}
+
+	locale = language
+}
+
+// Locale returns the current locale.
+func Locale() string {
+	return locale
+}
+
+// T returns the message translated to the current locale, with args interpolated as in fmt.Sprintf.
+// Messages missing from the current locale fall back to DefaultLocale, and unknown messages to their ID.
+func T(id MessageID, args ...interface{}) string {
+	message, exists := catalogs[locale][id]
+	if !exists {
+		message, exists = catalogs[DefaultLocale][id]
+	}
+	if !exists {
+		message = string(id)
+	}
+
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Len returns the number of characters in a message, for laying out monospaced text.
+func Len(message string) int {
+	return utf8.RuneCountInString(message)
+}
+
+// Fit abbreviates a message to at most maxLen characters, ending it with "." when shortened.
+func Fit(message string, maxLen int) string {
+	runes := []rune(message)
+	if len(runes) <= maxLen || maxLen <= 0 {
+		return message
+	}
+
+	// Drop trailing spaces so the abbreviation reads cleanly
+	abbreviated := strings.TrimRight(string(runes[:maxLen-1]), " ")
+	return abbreviated + "."
+}
@@ -0,0 +1,97 @@
+package i18n
+
+import "testing"
+
+func TestSetLocale(t *testing.T) {
+	defer SetLocale(DefaultLocale)
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"fr", "fr"},
+		{"fr_FR.UTF-8", "fr"},
+		{"FR-ca", "fr"},
+		{"en_US", "en"},
+		{"de_DE.UTF-8", DefaultLocale},
+		{"C", DefaultLocale},
+		{"", DefaultLocale},
+	}
+
+	for _, test := range tests {
+		SetLocale(test.name)
+		if got := Locale(); got != test.want {
+			t.Errorf("got locale %q for %q, want %q", got, test.name, test.want)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	defer SetLocale(DefaultLocale)
+
+	tests := []struct {
+		name   string
+		locale string
+		id     MessageID
+		args   []interface{}
+		want   string
+	}{
+		{"translated", "fr", GameOverL1, nil, "Partie terminée !"},
+		{"fallback to english", "fr", ModeCricket, nil, "Cricket"},
+		{"unknown message", "fr", MessageID("missing.message"), nil, "missing.message"},
+		{"interpolation", "en", MenuMode, []interface{}{"Cricket"}, "Mode: Cricket"},
+		{"translated interpolation", "fr", MenuMode, []interface{}{"Classique"}, "Jeu: Classique"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			SetLocale(test.locale)
+			if got := T(test.id, test.args...); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCatalogsFallBackToEnglish(t *testing.T) {
+	for locale, catalog := range catalogs {
+		for id := range catalog {
+			if _, exists := catalogs[DefaultLocale][id]; !exists {
+				t.Errorf("message %q in locale %q has no %q message to fall back to", id, locale, DefaultLocale)
+			}
+		}
+	}
+}
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		maxLen  int
+		want    string
+	}{
+		{"fits", "Classement", 20, "Classement"},
+		{"exactly fits", "Retour pour quitter.", 20, "Retour pour quitter."},
+		{"long french string", "Appuyez sur retour pour quitter.", 20, "Appuyez sur retour."},
+		{"cut within accented word", "Partie terminée !", 15, "Partie terminé."},
+		{"no limit", "Partie terminée !", 0, "Partie terminée !"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := Fit(test.message, test.maxLen)
+			if got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+			if test.maxLen > 0 && Len(got) > test.maxLen {
+				t.Errorf("got %d characters, want at most %d", Len(got), test.maxLen)
+			}
+		})
+	}
+}
+
+func TestLen(t *testing.T) {
+	if got := Len("terminée"); got != 8 {
+		t.Errorf("got length %d, want 8 characters rather than bytes", got)
+	}
+}
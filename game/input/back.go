@@ -28,6 +28,10 @@ func InitBack() {
 
 // Back returns true if the back button was pressed.
 func Back() bool {
+	if pressed, ok := simulated(ButtonBack); ok {
+		return pressed
+	}
+
 	if util.IsRasPi() {
 		if IsRPIOButtonJustPressed(backPin) {
 			return true
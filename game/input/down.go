@@ -28,6 +28,10 @@ func InitDown() {
 
 // Down returns true if the down button was pressed.
 func Down() bool {
+	if pressed, ok := simulated(ButtonDown); ok {
+		return pressed
+	}
+
 	if util.IsRasPi() {
 		if IsRPIOButtonJustPressed(downPin) {
 			return true
@@ -28,6 +28,10 @@ func InitEarth() {
 
 // Earth returns true if the earth button was pressed.
 func Earth() bool {
+	if pressed, ok := simulated(ButtonEarth); ok {
+		return pressed
+	}
+
 	if util.IsRasPi() {
 		if IsRPIOButtonJustPressed(earthPin) {
 			return true
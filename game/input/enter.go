@@ -28,6 +28,10 @@ func InitEnter() {
 
 // Enter returns true if the enter button was pressed.
 func Enter() bool {
+	if pressed, ok := simulated(ButtonEnter); ok {
+		return pressed
+	}
+
 	if util.IsRasPi() {
 		if IsRPIOButtonJustPressed(enterPin) {
 			return true
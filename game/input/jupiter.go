@@ -28,6 +28,10 @@ func InitJupiter() {
 
 // Jupiter returns true if the jupiter button was pressed.
 func Jupiter() bool {
+	if pressed, ok := simulated(ButtonJupiter); ok {
+		return pressed
+	}
+
 	if util.IsRasPi() {
 		if IsRPIOButtonJustPressed(jupiterPin) {
 			return true
@@ -28,6 +28,10 @@ func InitMars() {
 
 // Mars returns true if the mars button was pressed.
 func Mars() bool {
+	if pressed, ok := simulated(ButtonMars); ok {
+		return pressed
+	}
+
 	if util.IsRasPi() {
 		if IsRPIOButtonJustPressed(marsPin) {
 			return true
@@ -28,6 +28,10 @@ func InitMercury() {
 
 // Mercury returns true if the mercury button was pressed.
 func Mercury() bool {
+	if pressed, ok := simulated(ButtonMercury); ok {
+		return pressed
+	}
+
 	if util.IsRasPi() {
 		if IsRPIOButtonJustPressed(mercuryPin) {
 			return true
@@ -28,6 +28,10 @@ func InitPluto() {
 
 // Pluto returns true if the pluto button was pressed.
 func Pluto() bool {
+	if pressed, ok := simulated(ButtonPluto); ok {
+		return pressed
+	}
+
 	if util.IsRasPi() {
 		if IsRPIOButtonJustPressed(plutoPin) {
 			return true
@@ -28,6 +28,10 @@ func InitSaturn() {
 
 // Saturn returns true if the saturn button was pressed.
 func Saturn() bool {
+	if pressed, ok := simulated(ButtonSaturn); ok {
+		return pressed
+	}
+
 	if util.IsRasPi() {
 		if IsRPIOButtonJustPressed(saturnPin) {
 			return true
@@ -0,0 +1,62 @@
+package input
+
+import "sync"
+
+// Button identifies a cabinet button, or its keyboard equivalent.
+type Button int
+
+// Enumeration of buttons.
+const (
+	ButtonBack Button = iota
+	ButtonEnter
+	ButtonUp
+	ButtonDown
+	ButtonUndo
+	ButtonMercury
+	ButtonEarth
+	ButtonMars
+	ButtonJupiter
+	ButtonSaturn
+	ButtonPluto
+)
+
+// simulatedState holds scripted button presses replacing the keyboard and cabinet.
+type simulatedState struct {
+	pressed map[Button]bool // pressed contains the buttons pressed this frame, nil when not simulating.
+
+	m sync.RWMutex
+}
+
+// theSimulatedState is the scripted input state.
+var theSimulatedState = &simulatedState{}
+
+// Simulate replaces the keyboard and cabinet with the given buttons pressed, until the next call.
+// Calling it with no buttons presses nothing. Used to script input in tests.
+func Simulate(pressed ...Button) {
+	theSimulatedState.m.Lock()
+	defer theSimulatedState.m.Unlock()
+
+	theSimulatedState.pressed = map[Button]bool{}
+	for _, button := range pressed {
+		theSimulatedState.pressed[button] = true
+	}
+}
+
+// StopSimulating returns to reading the keyboard and cabinet.
+func StopSimulating() {
+	theSimulatedState.m.Lock()
+	defer theSimulatedState.m.Unlock()
+
+	theSimulatedState.pressed = nil
+}
+
+// simulated returns whether a button is pressed by the script, and false for ok if input isn't simulated.
+func simulated(button Button) (pressed, ok bool) {
+	theSimulatedState.m.RLock()
+	defer theSimulatedState.m.RUnlock()
+
+	if theSimulatedState.pressed == nil {
+		return false, false
+	}
+	return theSimulatedState.pressed[button], true
+}
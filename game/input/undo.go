@@ -0,0 +1,16 @@
+package input
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Undo returns true if the operator undo key was pressed.
+// There is deliberately no cabinet button for undo, only an attached keyboard can trigger it.
+func Undo() bool {
+	if pressed, ok := simulated(ButtonUndo); ok {
+		return pressed
+	}
+
+	return inpututil.IsKeyJustPressed(ebiten.KeyU)
+}
@@ -28,6 +28,10 @@ func InitUp() {
 
 // Up returns true if the up button was pressed.
 func Up() bool {
+	if pressed, ok := simulated(ButtonUp); ok {
+		return pressed
+	}
+
 	if util.IsRasPi() {
 		if IsRPIOButtonJustPressed(upPin) {
 			return true
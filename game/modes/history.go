@@ -0,0 +1,46 @@
+package modes
+
+// UndoDepth is how many of the most recent throws can be undone.
+const UndoDepth = 3
+
+// History keeps the points of the most recent throws of a game so they can be undone.
+type History struct {
+	depth  int   // depth is how many throws are kept.
+	points []int // points are the points of each kept throw, oldest first.
+}
+
+// NewHistory is a History factory, keeping up to depth throws.
+func NewHistory(depth int) *History {
+	return &History{
+		depth:  depth,
+		points: make([]int, 0, depth),
+	}
+}
+
+// Record keeps the points of a throw, forgetting the oldest throw once more than depth are kept.
+func (h *History) Record(points int) {
+	if len(h.points) == h.depth {
+		copy(h.points, h.points[1:])
+		h.points = h.points[:len(h.points)-1]
+	}
+	h.points = append(h.points, points)
+}
+
+// Undo forgets the most recent throw and reverses it in the mode it was scored in.
+// Returns the throw's points, or false if there is no throw left to undo.
+func (h *History) Undo(m Mode) (int, bool) {
+	if len(h.points) == 0 {
+		return 0, false
+	}
+
+	last := h.points[len(h.points)-1]
+	h.points = h.points[:len(h.points)-1]
+	m.Undo()
+
+	return last, true
+}
+
+// Len returns how many throws can be undone.
+func (h *History) Len() int {
+	return len(h.points)
+}
@@ -0,0 +1,96 @@
+package modes
+
+import "testing"
+
+// undoCounter is a Mode counting how many times it is undone.
+type undoCounter struct {
+	Mode
+	undos int // undos is the number of times Undo was called.
+}
+
+// Undo reverses the most recent Hit.
+func (m *undoCounter) Undo() {
+	m.undos++
+	m.Mode.Undo()
+}
+
+func TestHistoryTrimsAtDepth(t *testing.T) {
+	m := &undoCounter{Mode: ByName("Classic")}
+	h := NewHistory(UndoDepth)
+
+	throws := []int{10, 20, 30, 40, 50}
+	for _, points := range throws {
+		h.Record(points)
+	}
+	if h.Len() != UndoDepth {
+		t.Fatalf("got %d throws kept, want %d", h.Len(), UndoDepth)
+	}
+
+	// Only the most recent throws are undone, newest first
+	for i := len(throws) - 1; i >= len(throws)-UndoDepth; i-- {
+		points, ok := h.Undo(m)
+		if !ok || points != throws[i] {
+			t.Fatalf("got undo of %d points (%t), want %d", points, ok, throws[i])
+		}
+	}
+	if _, ok := h.Undo(m); ok {
+		t.Errorf("got an undo beyond depth %d, want none", UndoDepth)
+	}
+	if m.undos != UndoDepth {
+		t.Errorf("got mode undone %d times, want %d", m.undos, UndoDepth)
+	}
+}
+
+func TestHistoryUndoClosingBonus(t *testing.T) {
+	m := newCricketMode()
+	h := NewHistory(UndoDepth)
+
+	// Close every target, recording each throw
+	score := 0
+	for _, target := range cricketTargets {
+		for i := 0; i < cricketHitsToClose; i++ {
+			points := m.Hit(target)
+			score += points
+			h.Record(points)
+		}
+	}
+	if !m.Complete() {
+		t.Fatalf("got game incomplete with every target closed, want complete")
+	}
+
+	// Undoing the closing throw takes back its bonus and reopens the game
+	last := cricketTargets[len(cricketTargets)-1]
+	shotsLeft := cricketShots - len(cricketTargets)*cricketHitsToClose
+	want := planetPoints[last] + shotsLeft*cricketClosingBonus
+	points, ok := h.Undo(m)
+	if !ok || points != want {
+		t.Fatalf("got undo of %d points (%t) for the closing throw, want %d", points, ok, want)
+	}
+	if m.Complete() {
+		t.Errorf("got game complete after undoing the closing throw, want incomplete")
+	}
+
+	// Throwing it again scores the same bonus
+	if got := m.Hit(last); got != want {
+		t.Errorf("got %d points re-closing the game, want %d", got, want)
+	}
+}
+
+func TestHistoryUndoEmpty(t *testing.T) {
+	m := &undoCounter{Mode: newCricketMode()}
+	h := NewHistory(UndoDepth)
+
+	if points, ok := h.Undo(m); ok || points != 0 {
+		t.Errorf("got undo of %d points (%t) with no throws, want none", points, ok)
+	}
+	if m.undos != 0 {
+		t.Errorf("got mode undone %d times with no throws, want 0", m.undos)
+	}
+
+	// A throw recorded afterwards is still undone once
+	h.Record(m.Hit(PlanetMars))
+	h.Undo(m)
+	if _, ok := h.Undo(m); ok || m.undos != 1 {
+		t.Errorf("got mode undone %d times after one throw, want 1", m.undos)
+	}
+}
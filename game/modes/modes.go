@@ -0,0 +1,253 @@
+package modes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rytrose/soup-the-moon/game/i18n"
+	"github.com/rytrose/soup-the-moon/game/util"
+)
+
+// Planet identifies a scoring target.
+type Planet int
+
+// Enumeration of planets.
+const (
+	PlanetMercury Planet = iota
+	PlanetEarth
+	PlanetMars
+	PlanetJupiter
+	PlanetSaturn
+	PlanetPluto
+)
+
+// planetNames are the messages of the display names of the planets.
+var planetNames = map[Planet]i18n.MessageID{
+	PlanetMercury: i18n.PlanetMercury,
+	PlanetEarth:   i18n.PlanetEarth,
+	PlanetMars:    i18n.PlanetMars,
+	PlanetJupiter: i18n.PlanetJupiter,
+	PlanetSaturn:  i18n.PlanetSaturn,
+	PlanetPluto:   i18n.PlanetPluto,
+}
+
+// PlanetName returns the localized display name of a planet.
+func PlanetName(p Planet) string {
+	return i18n.T(planetNames[p])
+}
+
+// planetPoints are the points scored for hitting each planet.
+var planetPoints = map[Planet]int{
+	PlanetMercury: -250,
+	PlanetEarth:   250,
+	PlanetMars:    500,
+	PlanetJupiter: 1000,
+	PlanetSaturn:  2000,
+	PlanetPluto:   5000,
+}
+
+// classicShots is how many shot attempts a classic game starts with.
+const classicShots = 8
+
+// Mode is a set of game rules consulted by the scoring screen.
+type Mode interface {
+	// Name is the display name of the mode.
+	Name() string
+	// Reset prepares the mode for a new game.
+	Reset()
+	// Shots is how many shot attempts a game starts with.
+	Shots() int
+	// Hit records a shot landing on a planet and returns the points scored.
+	Hit(p Planet) int
+	// Undo reverses the most recent Hit.
+	Undo()
+	// Complete returns true if the game is over before running out of shots.
+	Complete() bool
+	// Display returns mode specific lines to draw on the scoring screen.
+	Display() []string
+}
+
+// modes are all selectable game modes.
+var modes = []Mode{
+	&classicMode{},
+	newCricketMode(),
+}
+
+// ByName finds a game mode by name, defaulting to the first mode.
+// Leaderboard entries recorded before modes existed have no name, so are treated as the first mode.
+func ByName(name string) Mode {
+	for _, mode := range modes {
+		if mode.Name() == name {
+			return mode
+		}
+	}
+	return modes[0]
+}
+
+// NextName returns the name of the mode following the named mode.
+func NextName(name string) string {
+	for i, mode := range modes {
+		if mode.Name() == name {
+			return modes[util.Mod(i+1, len(modes))].Name()
+		}
+	}
+
+	// Unknown names are treated as the first mode
+	return modes[util.Min(1, len(modes)-1)].Name()
+}
+
+// modeLabels are the messages of the localized labels of the modes, keyed by mode name.
+var modeLabels = map[string]i18n.MessageID{
+	"Classic": i18n.ModeClassic,
+	"Cricket": i18n.ModeCricket,
+}
+
+// Label returns the localized label of a mode.
+// The mode name is kept untranslated since it is persisted with leaderboard entries.
+func Label(m Mode) string {
+	return i18n.T(modeLabels[m.Name()])
+}
+
+// classicMode scores every hit and ends when shots run out.
+type classicMode struct{}
+
+// Name is the display name of the mode.
+func (m *classicMode) Name() string {
+	return "Classic"
+}
+
+// Reset prepares the mode for a new game.
+func (m *classicMode) Reset() {}
+
+// Shots is how many shot attempts a game starts with.
+func (m *classicMode) Shots() int {
+	return classicShots
+}
+
+// Hit records a shot landing on a planet and returns the points scored.
+func (m *classicMode) Hit(p Planet) int {
+	return planetPoints[p]
+}
+
+// Undo reverses the most recent Hit.
+func (m *classicMode) Undo() {}
+
+// Complete returns true if the game is over before running out of shots.
+func (m *classicMode) Complete() bool {
+	return false
+}
+
+// Display returns mode specific lines to draw on the scoring screen.
+func (m *classicMode) Display() []string {
+	return nil
+}
+
+// Cricket constants
+const (
+	cricketShots        = 20
+	cricketHitsToClose  = 3
+	cricketClosingBonus = 1000
+)
+
+// cricketTargets are the planets that must be closed out, in display order.
+var cricketTargets = []Planet{
+	PlanetEarth,
+	PlanetMars,
+	PlanetJupiter,
+	PlanetSaturn,
+	PlanetPluto,
+}
+
+// cricketMode requires hitting each target planet three times to close it out.
+// Hits on open targets score normally, extra hits on closed targets score nothing.
+// Closing every target ends the game with a bonus per remaining shot.
+type cricketMode struct {
+	hits    map[Planet]int
+	history []cricketShot
+}
+
+// cricketShot is a shot recorded by cricketMode, kept so it can be undone.
+type cricketShot struct {
+	planet Planet
+	marked bool
+}
+
+// newCricketMode is a cricketMode factory.
+func newCricketMode() *cricketMode {
+	return &cricketMode{
+		hits: map[Planet]int{},
+	}
+}
+
+// Name is the display name of the mode.
+func (m *cricketMode) Name() string {
+	return "Cricket"
+}
+
+// Reset prepares the mode for a new game.
+func (m *cricketMode) Reset() {
+	m.hits = map[Planet]int{}
+	m.history = nil
+}
+
+// Shots is how many shot attempts a game starts with.
+func (m *cricketMode) Shots() int {
+	return cricketShots
+}
+
+// Hit records a shot landing on a planet and returns the points scored.
+func (m *cricketMode) Hit(p Planet) int {
+	// Mercury is never a target and always penalizes
+	if p == PlanetMercury {
+		m.history = append(m.history, cricketShot{planet: p})
+		return planetPoints[p]
+	}
+
+	if m.hits[p] >= cricketHitsToClose {
+		m.history = append(m.history, cricketShot{planet: p})
+		return 0
+	}
+	m.hits[p]++
+	m.history = append(m.history, cricketShot{planet: p, marked: true})
+
+	points := planetPoints[p]
+	if m.Complete() {
+		// Award a bonus for each shot left over
+		points += (cricketShots - len(m.history)) * cricketClosingBonus
+	}
+	return points
+}
+
+// Undo reverses the most recent Hit.
+func (m *cricketMode) Undo() {
+	if len(m.history) == 0 {
+		return
+	}
+
+	// Remove the mark the shot added, if any
+	last := m.history[len(m.history)-1]
+	m.history = m.history[:len(m.history)-1]
+	if last.marked {
+		m.hits[last.planet]--
+	}
+}
+
+// Complete returns true if the game is over before running out of shots.
+func (m *cricketMode) Complete() bool {
+	for _, target := range cricketTargets {
+		if m.hits[target] < cricketHitsToClose {
+			return false
+		}
+	}
+	return true
+}
+
+// Display returns mode specific lines to draw on the scoring screen.
+func (m *cricketMode) Display() []string {
+	lines := make([]string, 0, len(cricketTargets))
+	for _, target := range cricketTargets {
+		marks := strings.Repeat("X", m.hits[target]) + strings.Repeat("-", cricketHitsToClose-m.hits[target])
+		lines = append(lines, fmt.Sprintf("%s %s", string([]rune(PlanetName(target))[:3]), marks))
+	}
+	return lines
+}
@@ -0,0 +1,154 @@
+package modes
+
+import (
+	"testing"
+
+	"github.com/rytrose/soup-the-moon/game/i18n"
+)
+
+func TestClassicHit(t *testing.T) {
+	m := ByName("Classic")
+	m.Reset()
+
+	for planet, points := range planetPoints {
+		if got := m.Hit(planet); got != points {
+			t.Errorf("got %d points for %s, want %d", got, PlanetName(planet), points)
+		}
+	}
+	if m.Complete() {
+		t.Errorf("got classic game complete, want it to end only when shots run out")
+	}
+}
+
+// hitAll hits each planet the given number of times, returning the total points scored.
+func hitAll(m Mode, planets []Planet, times int) int {
+	points := 0
+	for _, planet := range planets {
+		for i := 0; i < times; i++ {
+			points += m.Hit(planet)
+		}
+	}
+	return points
+}
+
+func TestCricketHit(t *testing.T) {
+	tests := []struct {
+		name   string
+		planet Planet
+		hits   int
+		want   int
+	}{
+		{"open target scores", PlanetMars, 1, planetPoints[PlanetMars]},
+		{"closing hit scores", PlanetMars, cricketHitsToClose, cricketHitsToClose * planetPoints[PlanetMars]},
+		{"closed target scores nothing", PlanetMars, cricketHitsToClose + 2, cricketHitsToClose * planetPoints[PlanetMars]},
+		{"mercury always penalizes", PlanetMercury, cricketHitsToClose + 1, (cricketHitsToClose + 1) * planetPoints[PlanetMercury]},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newCricketMode()
+			if got := hitAll(m, []Planet{test.planet}, test.hits); got != test.want {
+				t.Errorf("got %d points, want %d", got, test.want)
+			}
+			if m.Complete() {
+				t.Errorf("got game complete with targets open, want incomplete")
+			}
+		})
+	}
+}
+
+func TestCricketCompleteBonus(t *testing.T) {
+	m := newCricketMode()
+
+	// Close every target but the last
+	hitAll(m, cricketTargets[:len(cricketTargets)-1], cricketHitsToClose)
+	last := cricketTargets[len(cricketTargets)-1]
+	hitAll(m, []Planet{last}, cricketHitsToClose-1)
+	if m.Complete() {
+		t.Fatalf("got game complete with %s open, want incomplete", PlanetName(last))
+	}
+
+	// The closing shot earns a bonus for every shot left over
+	shotsTaken := len(cricketTargets) * cricketHitsToClose
+	want := planetPoints[last] + (cricketShots-shotsTaken)*cricketClosingBonus
+	if got := m.Hit(last); got != want {
+		t.Errorf("got %d points for the closing shot, want %d", got, want)
+	}
+	if !m.Complete() {
+		t.Errorf("got game incomplete with every target closed, want complete")
+	}
+}
+
+func TestCricketUndo(t *testing.T) {
+	m := newCricketMode()
+
+	// Undo with no shots does nothing
+	m.Undo()
+
+	// Undoing a hit on a closed target leaves it closed
+	hitAll(m, []Planet{PlanetPluto}, cricketHitsToClose+1)
+	m.Undo()
+	if m.hits[PlanetPluto] != cricketHitsToClose {
+		t.Errorf("got %d marks on a closed target after undoing an extra hit, want %d", m.hits[PlanetPluto], cricketHitsToClose)
+	}
+
+	// Undoing the closing hit reopens the target, so hitting it scores again
+	m.Undo()
+	if m.hits[PlanetPluto] != cricketHitsToClose-1 {
+		t.Errorf("got %d marks after undoing the closing hit, want %d", m.hits[PlanetPluto], cricketHitsToClose-1)
+	}
+	if got := m.Hit(PlanetPluto); got != planetPoints[PlanetPluto] {
+		t.Errorf("got %d points re-closing the target, want %d", got, planetPoints[PlanetPluto])
+	}
+
+	// Undoing a Mercury penalty leaves marks alone
+	m.Hit(PlanetMercury)
+	m.Undo()
+	if m.hits[PlanetPluto] != cricketHitsToClose || len(m.history) != cricketHitsToClose {
+		t.Errorf("got %d marks and %d shots after undoing a penalty, want %d of each", m.hits[PlanetPluto], len(m.history), cricketHitsToClose)
+	}
+
+	// Undo beyond the first shot stops there
+	for i := 0; i < cricketShots; i++ {
+		m.Undo()
+	}
+	if m.hits[PlanetPluto] != 0 || len(m.history) != 0 {
+		t.Errorf("got %d marks and %d shots after undoing everything, want none", m.hits[PlanetPluto], len(m.history))
+	}
+}
+
+func TestCricketDisplay(t *testing.T) {
+	i18n.SetLocale(i18n.DefaultLocale)
+	m := newCricketMode()
+	hitAll(m, []Planet{PlanetEarth}, 2)
+
+	lines := m.Display()
+	if len(lines) != len(cricketTargets) {
+		t.Fatalf("got %d display lines, want %d", len(lines), len(cricketTargets))
+	}
+	if want := "EAR XX-"; lines[0] != want {
+		t.Errorf("got line %q for Earth, want %q", lines[0], want)
+	}
+}
+
+func TestByNameAndNextName(t *testing.T) {
+	tests := []struct {
+		name     string
+		wantMode string
+		wantNext string
+	}{
+		{"Classic", "Classic", "Cricket"},
+		{"Cricket", "Cricket", "Classic"},
+		{"", "Classic", "Cricket"},
+		{"Unknown", "Classic", "Cricket"},
+	}
+
+	for _, test := range tests {
+		if got := ByName(test.name).Name(); got != test.wantMode {
+			t.Errorf("got mode %q by name %q, want %q", got, test.name, test.wantMode)
+		}
+		if got := NextName(test.name); got != test.wantNext {
+			t.Errorf("got next mode %q after %q, want %q", got, test.name, test.wantNext)
+		}
+	}
+}
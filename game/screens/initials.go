@@ -6,15 +6,14 @@ import (
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text"
 	"github.com/rytrose/soup-the-moon/game/audio"
+	"github.com/rytrose/soup-the-moon/game/flow"
 	"github.com/rytrose/soup-the-moon/game/fonts"
+	"github.com/rytrose/soup-the-moon/game/i18n"
 	"github.com/rytrose/soup-the-moon/game/input"
 	"github.com/rytrose/soup-the-moon/game/state"
 	"github.com/rytrose/soup-the-moon/game/util"
 )
 
-// tokens contains all possible initials tokens.
-var tokens = []string{"A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L", "M", "N", "O", "P", "Q", "R", "S", "T", "U", "V", "W", "X", "Y", "Z", "!", "?", "$", "*"}
-
 // initialsState maintains all state needed for the initials input screen.
 type initialsState struct {
 	selected     int
@@ -25,7 +24,7 @@ type initialsState struct {
 var theInitialsState = &initialsState{}
 
 // UpdateInitials updates initials input screen state before every frame.
-func UpdateInitials() ScreenID {
+func UpdateInitials() flow.Event {
 	// Play theme music
 	if !theInitialsState.playingTheme {
 		audio.InitialsThemePlayer.Rewind()
@@ -39,13 +38,13 @@ func UpdateInitials() ScreenID {
 			audio.InitialsThemePlayer.Pause()
 			theInitialsState.playingTheme = false
 
-			return ScreenMenu
+			return flow.EventBack
 		}
 
 		// Move cursor back
 		theInitialsState.selected--
 
-		return ScreenInitials
+		return flow.EventNone
 	}
 
 	if input.Enter() {
@@ -56,28 +55,28 @@ func UpdateInitials() ScreenID {
 			audio.InitialsThemePlayer.Pause()
 			theInitialsState.playingTheme = false
 
-			return ScreenScoring
+			return flow.EventInitialsEntered
 		}
 
 		// Move cursor forward
 		theInitialsState.selected++
 
-		return ScreenInitials
+		return flow.EventNone
 	}
 
 	if input.Up() {
 		// Change token
-		state.Global.CurrentInitials[theInitialsState.selected] = util.Mod(state.Global.CurrentInitials[theInitialsState.selected]-1, len(tokens))
-		state.Save()
+		state.Global.CurrentInitials[theInitialsState.selected] = util.Mod(state.Global.CurrentInitials[theInitialsState.selected]-1, len(state.InitialsTokens))
+		state.SaveLater()
 	}
 
 	if input.Down() {
 		// Change token
-		state.Global.CurrentInitials[theInitialsState.selected] = util.Mod(state.Global.CurrentInitials[theInitialsState.selected]+1, len(tokens))
-		state.Save()
+		state.Global.CurrentInitials[theInitialsState.selected] = util.Mod(state.Global.CurrentInitials[theInitialsState.selected]+1, len(state.InitialsTokens))
+		state.SaveLater()
 	}
 
-	return ScreenInitials
+	return flow.EventNone
 }
 
 // DrawInitials draws one frame of the initials input screen.
@@ -88,14 +87,14 @@ func DrawInitials(count uint64, w, h int, screen *ebiten.Image) {
 
 // drawInitialsPrompt draws the input prompt to the top of the screen.
 func drawInitialsPrompt(w int, screen *ebiten.Image) {
-	prompt1 := "Enter your"
-	prompt2 := "initials..."
+	prompt1 := i18n.Fit(i18n.T(i18n.InitialsPrompt1), w/32)
+	prompt2 := i18n.Fit(i18n.T(i18n.InitialsPrompt2), w/32)
 	prompt1Y := 3 * 32
 	prompt2Y := 5 * 32
 
 	// Draw the title centered
-	prompt1X := (w - len(prompt1)*32) / 2
-	prompt2X := (w - len(prompt2)*32) / 2
+	prompt1X := (w - i18n.Len(prompt1)*32) / 2
+	prompt2X := (w - i18n.Len(prompt2)*32) / 2
 	text.Draw(screen, prompt1, fonts.ArcadeFont32, prompt1X, prompt1Y, color.White)
 	text.Draw(screen, prompt2, fonts.ArcadeFont32, prompt2X, prompt2Y, color.White)
 }
@@ -114,7 +113,7 @@ func drawInitialsInitials(w int, screen *ebiten.Image) {
 	// Draw the initials
 	for i, initial := range state.Global.CurrentInitials {
 		// Draw initial
-		text.Draw(screen, tokens[initial], fonts.ArcadeFont32, initialsX[i], initialsY, color.White)
+		text.Draw(screen, state.InitialsTokens[initial], fonts.ArcadeFont32, initialsX[i], initialsY, color.White)
 
 		if i == theInitialsState.selected {
 			// Draw the cursors
@@ -123,12 +122,3 @@ func drawInitialsInitials(w int, screen *ebiten.Image) {
 		}
 	}
 }
-
-// getInitials retrieves the string initials from a list of token indices.
-func getInitials(indices []int) []string {
-	return []string{
-		tokens[indices[0]],
-		tokens[indices[1]],
-		tokens[indices[2]],
-	}
-}
@@ -3,12 +3,15 @@ package screens
 import (
 	"fmt"
 	"image/color"
-	"strings"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/rytrose/soup-the-moon/game/flow"
 	"github.com/rytrose/soup-the-moon/game/fonts"
+	"github.com/rytrose/soup-the-moon/game/i18n"
 	"github.com/rytrose/soup-the-moon/game/input"
+	"github.com/rytrose/soup-the-moon/game/modes"
 	"github.com/rytrose/soup-the-moon/game/state"
 	"github.com/rytrose/soup-the-moon/game/util"
 )
@@ -16,25 +19,56 @@ import (
 // leaderboardState maintains all state needed for the leaderboard screen.
 type leaderboardState struct {
 	index int
+	scope state.LeaderboardScope
+}
+
+// leaderboardScopes are the leaderboard scopes in the order they are cycled through.
+var leaderboardScopes = []state.LeaderboardScope{
+	state.ScopeAllTime,
+	state.ScopeDaily,
+	state.ScopeWeekly,
+}
+
+// leaderboardTitles are the messages of the titles displayed for each leaderboard scope.
+var leaderboardTitles = map[state.LeaderboardScope]i18n.MessageID{
+	state.ScopeAllTime: i18n.LeaderboardAll,
+	state.ScopeDaily:   i18n.LeaderboardDaily,
+	state.ScopeWeekly:  i18n.LeaderboardWeekly,
 }
 
 // theLeaderboardState is the state of the leaderboard screen.
 var theLeaderboardState = &leaderboardState{}
 
 // UpdateLeaderboard updates leaderboard screen state before every frame.
-func UpdateLeaderboard() ScreenID {
+func UpdateLeaderboard() flow.Event {
 	// Go back to the menu screen
 	if input.Back() {
-		// Reset scroll state
+		// Reset scroll and scope state
 		theLeaderboardState.index = 0
+		theLeaderboardState.scope = state.ScopeAllTime
+
+		return flow.EventBack
+	}
+
+	// Cycle through leaderboard scopes
+	if input.Enter() {
+		for i, scope := range leaderboardScopes {
+			if scope == theLeaderboardState.scope {
+				theLeaderboardState.scope = leaderboardScopes[util.Mod(i+1, len(leaderboardScopes))]
+				break
+			}
+		}
 
-		return ScreenMenu
+		// Start the new scope from the top
+		theLeaderboardState.index = 0
 	}
 
+	entries := leaderboardEntries()
+
 	// Scroll down menu
 	if input.Down() {
 		// Only allow scrolling down to reach the end of the list (i.e. index == len(entries) - 3)
-		theLeaderboardState.index = util.Min(theLeaderboardState.index+1, util.Max(len(state.Global.Leaderboard.Entries)-3, 0))
+		theLeaderboardState.index = util.Min(theLeaderboardState.index+1, util.Max(len(entries)-3, 0))
 	}
 
 	// Scroll up menu
@@ -43,7 +77,7 @@ func UpdateLeaderboard() ScreenID {
 		theLeaderboardState.index = util.Max(theLeaderboardState.index-1, 0)
 	}
 
-	return ScreenLeaderboard
+	return flow.EventNone
 }
 
 // DrawLeaderboard draws one frame of the menu screen.
@@ -54,29 +88,35 @@ func DrawLeaderboard(count uint64, w, h int, screen *ebiten.Image) {
 
 // drawLeaderboardTitle draws the title to the top of the screen.
 func drawLeaderboardTitle(w int, screen *ebiten.Image) {
-	title := "Top Astronauts"
+	title := i18n.Fit(i18n.T(leaderboardTitles[theLeaderboardState.scope]), w/32)
 
 	// Draw the title centered
-	titleX := (w - len(title)*32) / 2
+	titleX := (w - i18n.Len(title)*32) / 2
 	text.Draw(screen, title, fonts.ArcadeFont32, titleX, 4*16, color.White)
+
+	// Draw the ranked game mode centered below
+	mode := i18n.Fit(modes.Label(modes.ByName(state.Global.CurrentMode)), w/16)
+	modeX := (w - i18n.Len(mode)*16) / 2
+	text.Draw(screen, mode, fonts.ArcadeFont16, modeX, 6*16+8, color.White)
 }
 
 // drawLeaders draws the top scorers to the screen.
 func drawLeaders(w, h int, screen *ebiten.Image) {
-	if theLeaderboardState.index > len(state.Global.Leaderboard.Entries)-1 {
+	entries := leaderboardEntries()
+	if theLeaderboardState.index > len(entries)-1 {
 		return
 	}
 
 	i := theLeaderboardState.index
 	for i < theLeaderboardState.index+3 {
-		if i < len(state.Global.Leaderboard.Entries) {
-			entry := state.Global.Leaderboard.Entries[i]
+		if i < len(entries) {
+			entry := entries[i]
 
 			// Draw rank
 			text.Draw(screen, fmt.Sprintf("%d.", i+1), fonts.ArcadeFont16, w/16, ((10+((i-theLeaderboardState.index)*6))*16)-8, color.White)
 
 			// Draw initials
-			text.Draw(screen, strings.Join(getInitials(entry.Initials), " "), fonts.ArcadeFont32, (8*w)/32, (10+((i-theLeaderboardState.index)*6))*16, color.White)
+			text.Draw(screen, state.FormatInitials(entry.Initials), fonts.ArcadeFont32, (8*w)/32, (10+((i-theLeaderboardState.index)*6))*16, color.White)
 
 			// Draw timestamp
 			timestamp := entry.Timestamp.Format("1/2/06")
@@ -96,8 +136,22 @@ func drawLeaders(w, h int, screen *ebiten.Image) {
 		text.Draw(screen, "/\\", fonts.ArcadeFont16, (7*w)/8, (7*h)/8, color.White)
 	}
 
-	if theLeaderboardState.index+3 < len(state.Global.Leaderboard.Entries) {
+	if theLeaderboardState.index+3 < len(entries) {
 		// Draw down cursor
 		text.Draw(screen, "\\/", fonts.ArcadeFont16, (7*w)/8, (7*h)/8+32, color.White)
 	}
 }
+
+// leaderboardEntries returns the entries of the selected leaderboard scope set in the selected game mode.
+// Scores from different modes aren't comparable, so each mode is ranked separately.
+func leaderboardEntries() []*state.LeaderboardEntry {
+	mode := modes.ByName(state.Global.CurrentMode)
+
+	entries := []*state.LeaderboardEntry{}
+	for _, entry := range state.ScopedEntries(theLeaderboardState.scope, time.Now()) {
+		if modes.ByName(entry.Mode) == mode {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
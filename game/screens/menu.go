@@ -12,8 +12,12 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/text"
 	"github.com/rytrose/soup-the-moon/game/animation"
 	"github.com/rytrose/soup-the-moon/game/audio"
+	"github.com/rytrose/soup-the-moon/game/flow"
 	"github.com/rytrose/soup-the-moon/game/fonts"
+	"github.com/rytrose/soup-the-moon/game/i18n"
 	"github.com/rytrose/soup-the-moon/game/input"
+	"github.com/rytrose/soup-the-moon/game/modes"
+	"github.com/rytrose/soup-the-moon/game/state"
 	"github.com/rytrose/soup-the-moon/game/util"
 )
 
@@ -23,15 +27,24 @@ type MenuOption string
 // Option strings.
 var (
 	NewGame     MenuOption = "New Game"
+	GameMode    MenuOption = "Mode"
 	Leaderboard MenuOption = "Leaderboard"
 )
 
 // options are the various menu options.
 var options = []MenuOption{
 	NewGame,
+	GameMode,
 	Leaderboard,
 }
 
+// menuLabels are the messages of the labels displayed for each option.
+var menuLabels = map[MenuOption]i18n.MessageID{
+	NewGame:     i18n.MenuNewGame,
+	GameMode:    i18n.MenuMode,
+	Leaderboard: i18n.MenuLeaderboard,
+}
+
 // menuState maintains all state needed for the menu screen.
 type menuState struct {
 	playingTheme bool
@@ -54,7 +67,7 @@ const (
 )
 
 // UpdateMenu updates menu screen state before every frame.
-func UpdateMenu() ScreenID {
+func UpdateMenu() flow.Event {
 	// Play theme music
 	if !theMenuState.playingTheme {
 		audio.TitleThemePlayer.Rewind()
@@ -74,6 +87,13 @@ func UpdateMenu() ScreenID {
 
 	// Select option
 	if input.Enter() {
+		// Cycle through game modes without leaving the page
+		if options[theMenuState.selected] == GameMode {
+			state.Global.CurrentMode = modes.NextName(state.Global.CurrentMode)
+			state.SaveLater()
+			return flow.EventNone
+		}
+
 		// Stop theme music before leaving page
 		audio.TitleThemePlayer.Pause()
 		theMenuState.playingTheme = false
@@ -81,13 +101,13 @@ func UpdateMenu() ScreenID {
 		selectedOption := options[theMenuState.selected]
 		switch selectedOption {
 		case NewGame:
-			return ScreenInitials
+			return flow.EventNewGame
 		case Leaderboard:
-			return ScreenLeaderboard
+			return flow.EventLeaderboard
 		}
 	}
 
-	return ScreenMenu
+	return flow.EventNone
 }
 
 // DrawMenu draws one frame of the menu screen.
@@ -117,7 +137,13 @@ func drawMenuOptions(w int, screen *ebiten.Image) {
 
 	// Draw all options
 	for i, option := range options {
-		text.Draw(screen, string(option), fonts.ArcadeFont32, startingX+tab, startingY+i*64, color.White)
+		label := i18n.T(menuLabels[option])
+		if option == GameMode {
+			// Show the selected game mode
+			label = i18n.T(menuLabels[option], modes.Label(modes.ByName(state.Global.CurrentMode)))
+		}
+		label = i18n.Fit(label, (w-startingX-tab)/32)
+		text.Draw(screen, label, fonts.ArcadeFont32, startingX+tab, startingY+i*64, color.White)
 	}
 
 	// Draw cursor
@@ -7,21 +7,20 @@ import (
 	"image/color"
 	"log"
 	"math/rand"
-	"strings"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text"
 	"github.com/rytrose/soup-the-moon/game/animation"
+	"github.com/rytrose/soup-the-moon/game/flow"
 	"github.com/rytrose/soup-the-moon/game/fonts"
+	"github.com/rytrose/soup-the-moon/game/i18n"
 	"github.com/rytrose/soup-the-moon/game/images"
 	"github.com/rytrose/soup-the-moon/game/input"
+	"github.com/rytrose/soup-the-moon/game/modes"
 	"github.com/rytrose/soup-the-moon/game/state"
 )
 
-// numShots is how many shot attempts you start with.
-const numShots = 8
-
 // planetImageWidth is the width of the planet PNGs.
 const planetImageWidth = 280
 
@@ -63,8 +62,11 @@ func loadImage(b []byte) *ebiten.Image {
 
 // scoringState maintains all state needed for the scoring screen.
 type scoringState struct {
+	mode           modes.Mode
+	started        bool
 	score          int
 	shotsRemaining int
+	throws         *modes.History
 	gameOver       bool
 	confirmingBack bool
 	planetImage    *ebiten.Image
@@ -76,8 +78,7 @@ type scoringState struct {
 
 // theScoringState is the state of the scoring screen.
 var theScoringState = &scoringState{
-	shotsRemaining: numShots,
-	stars:          map[int]*animation.Star{},
+	stars: map[int]*animation.Star{},
 }
 
 // Star constants
@@ -88,17 +89,26 @@ const (
 )
 
 // UpdateScoring updates the scoring screen state before every frame.
-func UpdateScoring(w, h int) ScreenID {
-	// Set the game over flag when no more shots remain
-	if theScoringState.shotsRemaining == 0 {
+func UpdateScoring(w, h int) flow.Event {
+	// Start a new game in the selected mode
+	if !theScoringState.started {
+		theScoringState.mode = modes.ByName(state.Global.CurrentMode)
+		theScoringState.mode.Reset()
+		theScoringState.shotsRemaining = theScoringState.mode.Shots()
+		theScoringState.throws = modes.NewHistory(modes.UndoDepth)
+		theScoringState.started = true
+	}
+
+	// Set the game over flag when no more shots remain or the mode is complete
+	if theScoringState.shotsRemaining == 0 || theScoringState.mode.Complete() {
 		theScoringState.gameOver = true
 	}
 
 	if input.Back() {
 		if theScoringState.confirmingBack {
-			// Reset score and shots remaining
+			// Reset score and start a new game next time
 			theScoringState.score = 0
-			theScoringState.shotsRemaining = numShots
+			theScoringState.started = false
 
 			// Stop animating planet
 			theScoringState.planetImage = nil
@@ -110,7 +120,7 @@ func UpdateScoring(w, h int) ScreenID {
 			// Clear game over flag
 			theScoringState.gameOver = false
 
-			return ScreenMenu
+			return flow.EventQuit
 		}
 
 		if !theScoringState.gameOver {
@@ -125,7 +135,7 @@ func UpdateScoring(w, h int) ScreenID {
 			theScoringState.gameOver = false
 		}
 
-		return ScreenScoring
+		return flow.EventNone
 	}
 
 	if input.Enter() {
@@ -138,66 +148,99 @@ func UpdateScoring(w, h int) ScreenID {
 			// Save to leaderboard
 			initials := make([]int, 3)
 			copy(initials, state.Global.CurrentInitials)
-			state.AddLeaderboardEntry(&state.LeaderboardEntry{
+			err := state.AddLeaderboardEntry(&state.LeaderboardEntry{
 				Initials:  initials,
 				Score:     theScoringState.score,
+				Mode:      theScoringState.mode.Name(),
 				Timestamp: time.Now(),
 			})
+			if err != nil {
+				log.Printf("leaderboard entry not yet saved: %s", err)
+			}
 
 			// Stop animating planet
 			theScoringState.planetImage = nil
 			theScoringState.animatedText = nil
 
-			// Reset score and shots remaining
+			// Reset score and start a new game next time
 			theScoringState.score = 0
-			theScoringState.shotsRemaining = numShots
+			theScoringState.started = false
 
 			// Clear game over flag
 			theScoringState.gameOver = false
 
-			return ScreenMenu
+			return flow.EventGameOverContinued
 		}
 	}
 
+	// Undo the last throw, e.g. to settle a dispute
+	if input.Undo() && !theScoringState.confirmingBack {
+		undoThrow()
+
+		return flow.EventNone
+	}
+
 	// Update score
 	if input.Mercury() && !theScoringState.gameOver {
-		theScoringState.score += -250
+		scoreThrow(modes.PlanetMercury)
 		theScoringState.planetImage = mercuryImage
-		theScoringState.animatedText = animation.NewTextScale(0, w/2, 3*h/4, "MERCURY", fonts.ArcadeFont32, color.White, 0.05, 5, 24, 1)
-		theScoringState.shotsRemaining -= 1
+		theScoringState.animatedText = animation.NewTextScale(0, w/2, 3*h/4, modes.PlanetName(modes.PlanetMercury), fonts.ArcadeFont32, color.White, 0.05, 5, 24, 1)
 	}
 	if input.Earth() && !theScoringState.gameOver {
-		theScoringState.score += 250
+		scoreThrow(modes.PlanetEarth)
 		theScoringState.planetImage = earthImage
-		theScoringState.animatedText = animation.NewTextScale(0, w/2, 3*h/4, "EARTH", fonts.ArcadeFont32, color.White, 1.5, 2, 20, 3)
-		theScoringState.shotsRemaining -= 1
+		theScoringState.animatedText = animation.NewTextScale(0, w/2, 3*h/4, modes.PlanetName(modes.PlanetEarth), fonts.ArcadeFont32, color.White, 1.5, 2, 20, 3)
 	}
 	if input.Mars() && !theScoringState.gameOver {
-		theScoringState.score += 500
+		scoreThrow(modes.PlanetMars)
 		theScoringState.planetImage = marsImage
-		theScoringState.animatedText = animation.NewTextScale(0, w/2, 3*h/4, "MARS", fonts.ArcadeFont32, color.White, 2.0, 3, 10, 4)
-		theScoringState.shotsRemaining -= 1
+		theScoringState.animatedText = animation.NewTextScale(0, w/2, 3*h/4, modes.PlanetName(modes.PlanetMars), fonts.ArcadeFont32, color.White, 2.0, 3, 10, 4)
 	}
 	if input.Jupiter() && !theScoringState.gameOver {
-		theScoringState.score += 1000
+		scoreThrow(modes.PlanetJupiter)
 		theScoringState.planetImage = jupiterImage
-		theScoringState.animatedText = animation.NewTextScale(0, w/2, 3*h/4, "JUPITER", fonts.ArcadeFont32, color.White, 2.5, 4, 10, 3)
-		theScoringState.shotsRemaining -= 1
+		theScoringState.animatedText = animation.NewTextScale(0, w/2, 3*h/4, modes.PlanetName(modes.PlanetJupiter), fonts.ArcadeFont32, color.White, 2.5, 4, 10, 3)
 	}
 	if input.Saturn() && !theScoringState.gameOver {
-		theScoringState.score += 2000
+		scoreThrow(modes.PlanetSaturn)
 		theScoringState.planetImage = saturnImage
-		theScoringState.animatedText = animation.NewTextScale(0, w/2, 3*h/4, "SATURN", fonts.ArcadeFont64, color.White, 1.6, 3, 8, 5)
-		theScoringState.shotsRemaining -= 1
+		theScoringState.animatedText = animation.NewTextScale(0, w/2, 3*h/4, modes.PlanetName(modes.PlanetSaturn), fonts.ArcadeFont64, color.White, 1.6, 3, 8, 5)
 	}
 	if input.Pluto() && !theScoringState.gameOver {
-		theScoringState.score += 5000
+		scoreThrow(modes.PlanetPluto)
 		theScoringState.planetImage = plutoImage
-		theScoringState.animatedText = animation.NewTextScale(0, w/2, 3*h/4, "PLUTO", fonts.ArcadeFont16, color.White, 12.0, 5, 8, 6)
-		theScoringState.shotsRemaining -= 1
+		theScoringState.animatedText = animation.NewTextScale(0, w/2, 3*h/4, modes.PlanetName(modes.PlanetPluto), fonts.ArcadeFont16, color.White, 12.0, 5, 8, 6)
+	}
+
+	return flow.EventNone
+}
+
+// scoreThrow scores a throw landing on a planet and remembers it so it can be undone.
+func scoreThrow(p modes.Planet) {
+	points := theScoringState.mode.Hit(p)
+	theScoringState.score += points
+	theScoringState.shotsRemaining--
+
+	theScoringState.throws.Record(points)
+}
+
+// undoThrow reverses the most recent throw, including its effect on the game mode.
+func undoThrow() {
+	points, ok := theScoringState.throws.Undo(theScoringState.mode)
+	if !ok {
+		return
 	}
 
-	return ScreenScoring
+	// Take back the points and the shot
+	theScoringState.score -= points
+	theScoringState.shotsRemaining++
+
+	// Stop animating planet
+	theScoringState.planetImage = nil
+	theScoringState.animatedText = nil
+
+	// The game may no longer be over, re-evaluated on the next update
+	theScoringState.gameOver = false
 }
 
 // DrawScoring draws one frame of the scoring screen.
@@ -207,6 +250,7 @@ func DrawScoring(count uint64, w, h int, screen *ebiten.Image) {
 	drawScoringScore(w, screen)
 	drawScoringPlanetAnimation(w, h, screen)
 	drawScoringShotsRemaining(w, screen)
+	drawScoringModeDisplay(screen)
 
 	if theScoringState.confirmingBack {
 		drawScoringConfirmBack(w, screen)
@@ -223,7 +267,7 @@ func drawScoringPlayerInitials(screen *ebiten.Image) {
 	initialsX := 32
 
 	// Draw the intials space delimited
-	initialsString := strings.Join(getInitials(state.Global.CurrentInitials), " ")
+	initialsString := state.FormatInitials(state.Global.CurrentInitials)
 	text.Draw(screen, initialsString, fonts.ArcadeFont32, initialsX, initialsY, color.White)
 }
 
@@ -237,33 +281,53 @@ func drawScoringScore(w int, screen *ebiten.Image) {
 	text.Draw(screen, scoreString, fonts.ArcadeFont32, scoreX, scoreY, color.White)
 }
 
+// shotsPerRow is how many shots remaining are drawn per row, keeping them clear of the mode display.
+const shotsPerRow = 8
+
 // drawScoringShotsRemaining draws the current number of shots remaining.
 func drawScoringShotsRemaining(w int, screen *ebiten.Image) {
-	shotsY := 6 * 16
+	shotsStartingY := 6 * 16
 	scoreStartingX := w - 32
 
-	// Draw the shots remaining
+	// Draw the shots remaining, wrapping onto further rows
 	for i := 0; i < theScoringState.shotsRemaining; i++ {
-		shotsX := scoreStartingX - (16 * 2 * i) - 16
+		shotsX := scoreStartingX - (16 * 2 * (i % shotsPerRow)) - 16
+		shotsY := shotsStartingY + (i/shotsPerRow)*24
 		text.Draw(screen, "*", fonts.ArcadeFont16, shotsX, shotsY, color.White)
 	}
 }
 
+// drawScoringModeDisplay draws the game mode's display lines below the player initials.
+func drawScoringModeDisplay(screen *ebiten.Image) {
+	if !theScoringState.started {
+		return
+	}
+
+	lineStartingY := 6 * 16
+	lineX := 32
+
+	// Draw each line
+	for i, line := range theScoringState.mode.Display() {
+		text.Draw(screen, line, fonts.ArcadeFont16, lineX, lineStartingY+i*24, color.White)
+	}
+}
+
 // drawScoringConfirmBack draws a confirmation message for exiting.
 func drawScoringConfirmBack(w int, screen *ebiten.Image) {
-	confirmationStringL1 := "Return to base?"
-	confirmationStringL2 := "Press back to quit."
-	confirmationStringL3 := "Press forward"
-	confirmationStringL4 := "to resume."
+	maxLen := w / 32
+	confirmationStringL1 := i18n.Fit(i18n.T(i18n.ConfirmBackL1), maxLen)
+	confirmationStringL2 := i18n.Fit(i18n.T(i18n.ConfirmBackL2), maxLen)
+	confirmationStringL3 := i18n.Fit(i18n.T(i18n.ConfirmBackL3), maxLen)
+	confirmationStringL4 := i18n.Fit(i18n.T(i18n.ConfirmBackL4), maxLen)
 
 	confirmationYL1 := 12 * 16
-	confirmationXL1 := (w - len(confirmationStringL1)*32) / 2
+	confirmationXL1 := (w - i18n.Len(confirmationStringL1)*32) / 2
 	confirmationYL2 := 16 * 16
-	confirmationXL2 := (w - len(confirmationStringL2)*32) / 2
+	confirmationXL2 := (w - i18n.Len(confirmationStringL2)*32) / 2
 	confirmationYL3 := 20 * 16
-	confirmationXL3 := (w - len(confirmationStringL3)*32) / 2
+	confirmationXL3 := (w - i18n.Len(confirmationStringL3)*32) / 2
 	confirmationYL4 := 23 * 16
-	confirmationXL4 := (w - len(confirmationStringL4)*32) / 2
+	confirmationXL4 := (w - i18n.Len(confirmationStringL4)*32) / 2
 
 	// Draw the confirmation message
 	text.Draw(screen, confirmationStringL1, fonts.ArcadeFont32, confirmationXL1, confirmationYL1, color.White)
@@ -274,16 +338,17 @@ func drawScoringConfirmBack(w int, screen *ebiten.Image) {
 
 // drawScoringGameOver draws a game over message.
 func drawScoringGameOver(w int, screen *ebiten.Image) {
-	gameOverStringL1 := "Game over!"
-	gameOverStringL2 := "Press forward"
-	gameOverStringL3 := "to continue."
+	maxLen := w / 32
+	gameOverStringL1 := i18n.Fit(i18n.T(i18n.GameOverL1), maxLen)
+	gameOverStringL2 := i18n.Fit(i18n.T(i18n.GameOverL2), maxLen)
+	gameOverStringL3 := i18n.Fit(i18n.T(i18n.GameOverL3), maxLen)
 
 	gameOverYL1 := 12 * 16
-	gameOverXL1 := (w - len(gameOverStringL1)*32) / 2
+	gameOverXL1 := (w - i18n.Len(gameOverStringL1)*32) / 2
 	gameOverYL2 := 16 * 16
-	gameOverXL2 := (w - len(gameOverStringL2)*32) / 2
+	gameOverXL2 := (w - i18n.Len(gameOverStringL2)*32) / 2
 	gameOverYL3 := 20 * 16
-	gameOverXL3 := (w - len(gameOverStringL3)*32) / 2
+	gameOverXL3 := (w - i18n.Len(gameOverStringL3)*32) / 2
 
 	// Draw the gameOver message
 	text.Draw(screen, gameOverStringL1, fonts.ArcadeFont32, gameOverXL1, gameOverYL1, color.White)
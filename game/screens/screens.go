@@ -1,12 +1,32 @@
 package screens
 
-// ScreenID identifies the screen to be displayed.
-type ScreenID int
-
-// Enumeration of screens.
-const (
-	ScreenMenu ScreenID = iota
-	ScreenLeaderboard
-	ScreenInitials
-	ScreenScoring
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/rytrose/soup-the-moon/game/flow"
 )
+
+// Screen is a state of the screen state machine.
+type Screen struct {
+	Update func(w, h int) flow.Event                               // Update updates screen state before every frame, returning what happened.
+	Draw   func(count uint64, w, h int, screenImage *ebiten.Image) // Draw draws one frame of the screen.
+}
+
+// Screens are all states of the screen state machine, whose transitions are declared by flow.Transitions.
+var Screens = map[flow.ScreenID]Screen{
+	flow.ScreenMenu: {
+		Update: func(w, h int) flow.Event { return UpdateMenu() },
+		Draw:   DrawMenu,
+	},
+	flow.ScreenLeaderboard: {
+		Update: func(w, h int) flow.Event { return UpdateLeaderboard() },
+		Draw:   DrawLeaderboard,
+	},
+	flow.ScreenInitials: {
+		Update: func(w, h int) flow.Event { return UpdateInitials() },
+		Draw:   DrawInitials,
+	},
+	flow.ScreenScoring: {
+		Update: UpdateScoring,
+		Draw:   DrawScoring,
+	},
+}
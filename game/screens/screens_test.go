@@ -0,0 +1,127 @@
+package screens
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rytrose/soup-the-moon/game/flow"
+	"github.com/rytrose/soup-the-moon/game/input"
+	"github.com/rytrose/soup-the-moon/game/state"
+)
+
+// testWidth and testHeight are the screen size updates are run at.
+const (
+	testWidth  = 1280
+	testHeight = 1024
+)
+
+// screenWalk drives the screen state machine with scripted input, recording the transitions taken.
+type screenWalk struct {
+	t      *testing.T
+	screen flow.ScreenID            // screen is the current screen.
+	taken  map[flow.Transition]bool // taken contains the transitions taken so far.
+}
+
+// newScreenWalk starts a walk on the menu with an empty state, failing the test on any undeclared transition.
+func newScreenWalk(t *testing.T) *screenWalk {
+	state.UseFile(filepath.Join(t.TempDir(), "state.gob"))
+	t.Cleanup(input.StopSimulating)
+
+	hook := flow.UndeclaredTransitionHook
+	flow.UndeclaredTransitionHook = func(from flow.ScreenID, event flow.Event) {
+		t.Errorf("got undeclared transition from %s on %s", from, event)
+	}
+	t.Cleanup(func() { flow.UndeclaredTransitionHook = hook })
+
+	return &screenWalk{
+		t:      t,
+		screen: flow.ScreenMenu,
+		taken:  map[flow.Transition]bool{},
+	}
+}
+
+// press runs one frame of the current screen's update with the buttons pressed.
+func (w *screenWalk) press(buttons ...input.Button) flow.Event {
+	input.Simulate(buttons...)
+	from := w.screen
+	event := Screens[from].Update(testWidth, testHeight)
+	w.screen = flow.Next(from, event)
+	if event != flow.EventNone {
+		w.taken[flow.Transition{From: from, On: event, To: w.screen}] = true
+	}
+	return event
+}
+
+// expect fails the test unless the walk is on the screen.
+func (w *screenWalk) expect(screen flow.ScreenID) {
+	if w.screen != screen {
+		w.t.Fatalf("got screen %s, want %s", w.screen, screen)
+	}
+}
+
+// choose selects a menu option.
+func (w *screenWalk) choose(option MenuOption) {
+	w.expect(flow.ScreenMenu)
+	for options[theMenuState.selected] != option {
+		w.press(input.ButtonDown)
+	}
+	w.press(input.ButtonEnter)
+}
+
+// enterInitials accepts the current initials.
+func (w *screenWalk) enterInitials() {
+	w.expect(flow.ScreenInitials)
+	for range state.Global.CurrentInitials {
+		w.press(input.ButtonEnter)
+	}
+}
+
+func TestScreensEmitDeclaredTransitions(t *testing.T) {
+	w := newScreenWalk(t)
+
+	// Into the leaderboard and back
+	w.choose(Leaderboard)
+	w.expect(flow.ScreenLeaderboard)
+	w.press(input.ButtonEnter)
+	w.press(input.ButtonBack)
+	w.expect(flow.ScreenMenu)
+
+	// Into initials and back from the first initial
+	w.choose(NewGame)
+	w.press(input.ButtonUp)
+	w.press(input.ButtonBack)
+	w.expect(flow.ScreenMenu)
+
+	// Into a game and quit
+	w.choose(NewGame)
+	w.enterInitials()
+	w.expect(flow.ScreenScoring)
+	w.press(input.ButtonEarth)
+	w.press(input.ButtonBack)
+	w.press(input.ButtonBack)
+	w.expect(flow.ScreenMenu)
+
+	// Play a game to the end and continue
+	w.choose(NewGame)
+	w.enterInitials()
+	w.press()
+	for shots := theScoringState.shotsRemaining; shots > 0; shots-- {
+		w.press(input.ButtonMars)
+	}
+	w.press(input.ButtonUndo)
+	w.press(input.ButtonMars)
+	w.press()
+	w.press(input.ButtonEnter)
+	w.expect(flow.ScreenMenu)
+	if entries := state.ScopedEntries(state.ScopeAllTime, time.Now()); len(entries) != 1 {
+		t.Errorf("got %d leaderboard entries after a game, want 1", len(entries))
+	}
+
+	// Every declared transition was emitted by a screen
+	for _, transition := range flow.Transitions {
+		if !w.taken[transition] {
+			t.Errorf("got no %s to %s transition on %s from scripted input, want one", transition.From, transition.To, transition.On)
+		}
+	}
+}
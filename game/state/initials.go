@@ -0,0 +1,15 @@
+package state
+
+import "strings"
+
+// InitialsTokens are the tokens initials are chosen from, saved initials being indices into them.
+var InitialsTokens = []string{"A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L", "M", "N", "O", "P", "Q", "R", "S", "T", "U", "V", "W", "X", "Y", "Z", "!", "?", "$", "*"}
+
+// FormatInitials formats initials from a list of token indices for display, e.g. "A B C".
+func FormatInitials(indices []int) string {
+	initials := make([]string, len(indices))
+	for i, index := range indices {
+		initials[i] = InitialsTokens[index]
+	}
+	return strings.Join(initials, " ")
+}
@@ -0,0 +1,82 @@
+package state
+
+import (
+	"io"
+	"log"
+	"time"
+)
+
+// saveBatchInterval is the longest a deferred save waits before being written.
+const saveBatchInterval = 5 * time.Second
+
+// persistence batches state writes to limit wear on the SD card.
+type persistence struct {
+	dirty      bool      // dirty is set when a deferred save is pending.
+	dirtySince time.Time // dirtySince is when the pending deferred save was first requested.
+	saves      int       // saves is the number of times the state file has been written.
+	bytes      int64     // bytes is the total number of bytes written to the state file.
+}
+
+// thePersistence is the persistence state of the state file.
+var thePersistence = &persistence{}
+
+// SaveLater marks the state as needing to be saved, batching it with other deferred saves.
+// Use for frequent, low value changes. Changes that must not be lost should use Save.
+func SaveLater() {
+	stateMutex.Lock()
+	defer stateMutex.Unlock()
+
+	if !thePersistence.dirty {
+		thePersistence.dirty = true
+		thePersistence.dirtySince = time.Now()
+	}
+}
+
+// Update writes a deferred save once it has waited saveBatchInterval.
+// A failed write is logged and retried after another saveBatchInterval.
+// Update should be called in the game update loop.
+func Update() {
+	stateMutex.Lock()
+	defer stateMutex.Unlock()
+
+	if thePersistence.dirty && time.Since(thePersistence.dirtySince) >= saveBatchInterval {
+		if err := save(); err != nil {
+			log.Printf("unable to write deferred save, retrying: %s", err)
+			thePersistence.dirtySince = time.Now()
+		}
+	}
+}
+
+// Flush writes any deferred save immediately, e.g. before exiting.
+// A failed write is logged and left pending.
+func Flush() {
+	stateMutex.Lock()
+	defer stateMutex.Unlock()
+
+	if thePersistence.dirty {
+		if err := save(); err != nil {
+			log.Printf("unable to flush deferred save: %s", err)
+		}
+	}
+}
+
+// WriteStats returns how many times and how many bytes in total the state file has been written.
+func WriteStats() (saves int, bytes int64) {
+	stateMutex.Lock()
+	defer stateMutex.Unlock()
+
+	return thePersistence.saves, thePersistence.bytes
+}
+
+// countingWriter counts bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write writes to the underlying writer, counting bytes written.
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -2,15 +2,19 @@ package state
 
 import (
 	"encoding/gob"
+	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"sort"
+	"sync"
 	"time"
 )
 
 // State is the state of the game that is persisted to a local file.
 type State struct {
 	CurrentInitials []int
+	CurrentMode     string
 	Leaderboard     *Leaderboard
 }
 
@@ -23,20 +27,39 @@ type Leaderboard struct {
 type LeaderboardEntry struct {
 	Initials  []int
 	Score     int
+	Mode      string
 	Timestamp time.Time
 }
 
+// LeaderboardScope identifies the period of time a leaderboard covers.
+type LeaderboardScope int
+
+// Enumeration of leaderboard scopes.
+const (
+	ScopeAllTime LeaderboardScope = iota
+	ScopeDaily
+	ScopeWeekly
+)
+
+// DayStartHour is the local hour at which a new leaderboard day, and on Mondays a new week, begins.
+const DayStartHour = 6
+
 // Global contains global game state that is persisted to a local file.
 var Global *State
 
 // statePath is the path to the state file.
-const statePath = "state.gob"
+var statePath = "state.gob"
+
+// stateMutex serializes changes to Global with writes of the state file,
+// so a save never interleaves with a read-modify-write of the leaderboard.
+var stateMutex sync.Mutex
 
 func init() {
 	load()
 }
 
 // load loads state from file, or creates an empty state.
+// Nothing is written until the state changes, so tools that only read the state never write the file.
 func load() {
 	stateFile, err := os.Open(statePath)
 	if err != nil {
@@ -50,11 +73,9 @@ func load() {
 				Entries: []*LeaderboardEntry{},
 			},
 		}
-
-		// Save off new state
-		Save()
 		return
 	}
+	defer stateFile.Close()
 
 	// Decode state from file
 	s := &State{}
@@ -73,24 +94,76 @@ func load() {
 			},
 		}
 	}
+}
 
-	// Save off loaded state
-	Save()
+// UseFile switches to another state file, replacing the current state with the state loaded from it.
+// As with the default file, nothing is written until the state changes.
+func UseFile(path string) {
+	stateMutex.Lock()
+	defer stateMutex.Unlock()
+
+	statePath = path
+	load()
+	thePersistence = &persistence{}
 }
 
-// Save saves the current state to a local file.
+// Save saves the current state to a local file immediately, including any deferred save.
 func Save() {
-	stateFile, err := os.Create(statePath)
+	stateMutex.Lock()
+	defer stateMutex.Unlock()
+
+	if err := save(); err != nil {
+		log.Fatalf("unable to save state: %s", err)
+	}
+}
+
+// save durably writes the current state to the state file. stateMutex must be held.
+// The state is written to a temporary file that replaces the state file once synced,
+// so a crash or power loss mid-write leaves the previous state intact.
+func save() error {
+	tempPath := statePath + ".tmp"
+	stateFile, err := os.Create(tempPath)
 	if err != nil {
-		log.Fatalf("unable to create stateFile: %s", err)
+		return fmt.Errorf("unable to create stateFile: %s", err)
+	}
+
+	counter := &countingWriter{w: stateFile}
+	stateEncoder := gob.NewEncoder(counter)
+	if err := stateEncoder.Encode(Global); err != nil {
+		stateFile.Close()
+		return fmt.Errorf("unable to encode state: %s", err)
+	}
+	if err := stateFile.Sync(); err != nil {
+		stateFile.Close()
+		return fmt.Errorf("unable to sync stateFile: %s", err)
+	}
+	if err := stateFile.Close(); err != nil {
+		return fmt.Errorf("unable to close stateFile: %s", err)
+	}
+	if err := os.Rename(tempPath, statePath); err != nil {
+		return fmt.Errorf("unable to replace stateFile: %s", err)
 	}
 
-	stateEncoder := gob.NewEncoder(stateFile)
-	stateEncoder.Encode(Global)
+	// Sync the directory so the rename survives power loss, where supported
+	if dir, err := os.Open(filepath.Dir(statePath)); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+
+	// Account for the write
+	thePersistence.dirty = false
+	thePersistence.saves++
+	thePersistence.bytes += counter.n
+
+	return nil
 }
 
-// AddLeaderboardEntry adds an entry to the leaderboard.
-func AddLeaderboardEntry(entry *LeaderboardEntry) {
+// AddLeaderboardEntry adds an entry to the leaderboard, returning once it has been durably saved.
+// If saving fails the entry is kept and will be written by the next successful save.
+func AddLeaderboardEntry(entry *LeaderboardEntry) error {
+	stateMutex.Lock()
+	defer stateMutex.Unlock()
+
 	// Add entry
 	Global.Leaderboard.Entries = append(Global.Leaderboard.Entries, entry)
 
@@ -100,5 +173,60 @@ func AddLeaderboardEntry(entry *LeaderboardEntry) {
 	})
 
 	// Save off edited state
-	Save()
+	if err := save(); err != nil {
+		thePersistence.dirty = true
+		thePersistence.dirtySince = time.Now()
+		return fmt.Errorf("unable to save leaderboard entry: %s", err)
+	}
+	return nil
+}
+
+// ScopedEntries returns the leaderboard entries, best first, set during the scope's period containing t.
+// Periods are derived from entry timestamps, so e.g. the daily leaderboard of a past day can be queried
+// by passing any time during that day, regardless of whether the game was running at rotation.
+func ScopedEntries(scope LeaderboardScope, t time.Time) []*LeaderboardEntry {
+	stateMutex.Lock()
+	defer stateMutex.Unlock()
+
+	var start, end time.Time
+	switch scope {
+	case ScopeDaily:
+		start = dayStart(t)
+		end = start.AddDate(0, 0, 1)
+	case ScopeWeekly:
+		start = weekStart(t)
+		end = start.AddDate(0, 0, 7)
+	default:
+		// Copy so later entries don't change the returned ranking
+		entries := make([]*LeaderboardEntry, len(Global.Leaderboard.Entries))
+		copy(entries, Global.Leaderboard.Entries)
+		return entries
+	}
+
+	// Entries are kept sorted by score, so filtering preserves the ranking
+	entries := []*LeaderboardEntry{}
+	for _, entry := range Global.Leaderboard.Entries {
+		if !entry.Timestamp.Before(start) && entry.Timestamp.Before(end) {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// dayStart returns the start of the leaderboard day containing t.
+func dayStart(t time.Time) time.Time {
+	start := time.Date(t.Year(), t.Month(), t.Day(), DayStartHour, 0, 0, 0, t.Location())
+	if t.Before(start) {
+		// Early morning belongs to the previous leaderboard day
+		start = start.AddDate(0, 0, -1)
+	}
+	return start
+}
+
+// weekStart returns the start of the leaderboard week, beginning on Monday, containing t.
+func weekStart(t time.Time) time.Time {
+	start := dayStart(t)
+	daysSinceMonday := (int(start.Weekday()) + 6) % 7
+	return start.AddDate(0, 0, -daysSinceMonday)
 }
@@ -0,0 +1,276 @@
+package state
+
+import (
+	"encoding/gob"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// useTempState points the state file at a temporary directory and starts from an empty state.
+func useTempState(t *testing.T) {
+	previousPath := statePath
+	statePath = filepath.Join(t.TempDir(), "state.gob")
+	t.Cleanup(func() { statePath = previousPath })
+
+	Global = &State{
+		CurrentInitials: make([]int, 3),
+		Leaderboard: &Leaderboard{
+			Entries: []*LeaderboardEntry{},
+		},
+	}
+	thePersistence = &persistence{}
+}
+
+// readStateFile decodes the state file.
+func readStateFile(t *testing.T) *State {
+	stateFile, err := os.Open(statePath)
+	if err != nil {
+		t.Fatalf("unable to open stateFile: %s", err)
+	}
+	defer stateFile.Close()
+
+	s := &State{}
+	if err := gob.NewDecoder(stateFile).Decode(s); err != nil {
+		t.Fatalf("unable to decode stateFile: %s", err)
+	}
+	return s
+}
+
+func TestConcurrentGamesLoseNoScores(t *testing.T) {
+	useTempState(t)
+
+	// Finish many games at once, interleaved with deferred saves from other screens
+	games := 200
+	scores := make([]int, games)
+	for i := range scores {
+		scores[i] = rand.Intn(100000)
+	}
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < games; i++ {
+		wg.Add(1)
+		go func(score int) {
+			defer wg.Done()
+			err := AddLeaderboardEntry(&LeaderboardEntry{
+				Initials:  []int{0, 1, 2},
+				Score:     score,
+				Timestamp: time.Now(),
+			})
+			if err != nil {
+				t.Errorf("unable to add entry: %s", err)
+			}
+			SaveLater()
+			Update()
+		}(scores[i])
+	}
+	wg.Wait()
+
+	// Every score made it to the file, in ranked order
+	sort.Sort(sort.Reverse(sort.IntSlice(scores)))
+	entries := readStateFile(t).Leaderboard.Entries
+	if len(entries) != games {
+		t.Fatalf("got %d entries in stateFile, want %d", len(entries), games)
+	}
+	for i, entry := range entries {
+		if entry.Score != scores[i] {
+			t.Fatalf("got score %d at rank %d, want %d", entry.Score, i+1, scores[i])
+		}
+	}
+}
+
+func TestUseFile(t *testing.T) {
+	useTempState(t)
+	saved := statePath
+	if err := AddLeaderboardEntry(&LeaderboardEntry{Initials: []int{0, 0, 0}, Score: 500}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A missing file gives an empty state without writing it
+	missing := filepath.Join(filepath.Dir(saved), "missing.gob")
+	UseFile(missing)
+	if len(Global.Leaderboard.Entries) != 0 {
+		t.Errorf("got %d entries from a missing file, want none", len(Global.Leaderboard.Entries))
+	}
+	if _, err := os.Stat(missing); !os.IsNotExist(err) {
+		t.Errorf("got missing file written by UseFile, want it left alone")
+	}
+
+	// Switching back loads the saved state
+	UseFile(saved)
+	if entries := Global.Leaderboard.Entries; len(entries) != 1 || entries[0].Score != 500 {
+		t.Errorf("got entries %v after switching back, want the saved entry", entries)
+	}
+}
+
+func TestFailedSaveIsRetried(t *testing.T) {
+	useTempState(t)
+	writablePath := statePath
+
+	// Saving into a missing directory fails
+	statePath = filepath.Join(filepath.Dir(writablePath), "missing", "state.gob")
+	if err := AddLeaderboardEntry(&LeaderboardEntry{Initials: []int{0, 0, 0}, Score: 500}); err == nil {
+		t.Fatalf("got no error saving into a missing directory, want one")
+	}
+	if len(Global.Leaderboard.Entries) != 1 {
+		t.Fatalf("got %d entries after a failed save, want the entry kept", len(Global.Leaderboard.Entries))
+	}
+
+	// A due deferred save that fails again is logged rather than exiting
+	thePersistence.dirtySince = time.Now().Add(-saveBatchInterval)
+	Update()
+	if !thePersistence.dirty {
+		t.Fatalf("got no pending save after a failed deferred save, want one")
+	}
+
+	// Once writable, the entry is saved
+	statePath = writablePath
+	Flush()
+	if thePersistence.dirty {
+		t.Errorf("got a pending save after flushing, want none")
+	}
+	if entries := readStateFile(t).Leaderboard.Entries; len(entries) != 1 || entries[0].Score != 500 {
+		t.Errorf("got entries %v in stateFile, want the retried entry", entries)
+	}
+}
+
+func TestSaveLaterBatches(t *testing.T) {
+	useTempState(t)
+
+	// A burst of deferred saves isn't written until the batch interval passes
+	for i := 0; i < 10; i++ {
+		Global.CurrentMode = fmt.Sprintf("mode %d", i)
+		SaveLater()
+		Update()
+	}
+	if saves, _ := WriteStats(); saves != 0 {
+		t.Fatalf("got %d saves before the batch interval, want 0", saves)
+	}
+	if _, err := os.Stat(statePath); !os.IsNotExist(err) {
+		t.Fatalf("got stateFile written before the batch interval, want none")
+	}
+
+	// Once due, the burst is written once
+	thePersistence.dirtySince = time.Now().Add(-saveBatchInterval)
+	Update()
+	Update()
+	if saves, bytes := WriteStats(); saves != 1 || bytes == 0 {
+		t.Fatalf("got %d saves of %d bytes after the batch interval, want 1 save", saves, bytes)
+	}
+	if mode := readStateFile(t).CurrentMode; mode != "mode 9" {
+		t.Errorf("got mode %q in stateFile, want the last change", mode)
+	}
+
+	// Flush writes a pending save without waiting, and only when one is pending
+	SaveLater()
+	Flush()
+	Flush()
+	if saves, _ := WriteStats(); saves != 2 {
+		t.Errorf("got %d saves after flushing, want 2", saves)
+	}
+}
+
+// venue is the time zone of leaderboard tests.
+var venue = time.FixedZone("venue", -5*60*60)
+
+// at returns a time on a day of October 2026 at the venue, where the 12th is a Monday.
+func at(day, hour, minute int) time.Time {
+	return time.Date(2026, time.October, day, hour, minute, 0, 0, venue)
+}
+
+func TestDayAndWeekStart(t *testing.T) {
+	tests := []struct {
+		name     string
+		t        time.Time
+		wantDay  time.Time
+		wantWeek time.Time
+	}{
+		{"at day start", at(14, 6, 0), at(14, 6, 0), at(12, 6, 0)},
+		{"just before day start", at(14, 5, 59), at(13, 6, 0), at(12, 6, 0)},
+		{"late evening", at(14, 23, 30), at(14, 6, 0), at(12, 6, 0)},
+		{"monday at day start", at(12, 6, 0), at(12, 6, 0), at(12, 6, 0)},
+		{"monday before day start", at(12, 5, 0), at(11, 6, 0), at(5, 6, 0)},
+		{"sunday night", at(18, 23, 0), at(18, 6, 0), at(12, 6, 0)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := dayStart(test.t); !got.Equal(test.wantDay) {
+				t.Errorf("got day start %s, want %s", got, test.wantDay)
+			}
+			if got := weekStart(test.t); !got.Equal(test.wantWeek) {
+				t.Errorf("got week start %s, want %s", got, test.wantWeek)
+			}
+		})
+	}
+}
+
+func TestScopedEntries(t *testing.T) {
+	useTempState(t)
+
+	// Entries set over two weeks, best first, with no games at all on the 10th
+	Global.Leaderboard.Entries = []*LeaderboardEntry{
+		{Score: 9000, Timestamp: at(8, 20, 0)},
+		{Score: 8000, Timestamp: at(14, 5, 30)},
+		{Score: 7000, Timestamp: at(14, 6, 0)},
+		{Score: 6000, Timestamp: at(12, 5, 59)},
+		{Score: 5000, Timestamp: at(15, 1, 0)},
+		{Score: 4000, Timestamp: at(12, 6, 0)},
+	}
+
+	tests := []struct {
+		name  string
+		scope LeaderboardScope
+		t     time.Time
+		want  []int
+	}{
+		{"all time", ScopeAllTime, at(14, 12, 0), []int{9000, 8000, 7000, 6000, 5000, 4000}},
+		{"today includes after midnight", ScopeDaily, at(15, 2, 0), []int{7000, 5000}},
+		{"early morning is the previous day", ScopeDaily, at(14, 5, 45), []int{8000}},
+		{"missed day is empty", ScopeDaily, at(10, 12, 0), []int{}},
+		{"past day queried later", ScopeDaily, at(12, 6, 0), []int{4000}},
+		{"this week", ScopeWeekly, at(16, 12, 0), []int{8000, 7000, 5000, 4000}},
+		{"monday before day start is the previous week", ScopeWeekly, at(12, 5, 59), []int{9000, 6000}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entries := ScopedEntries(test.scope, test.t)
+			scores := []int{}
+			for _, entry := range entries {
+				scores = append(scores, entry.Score)
+			}
+
+			if len(scores) != len(test.want) {
+				t.Fatalf("got scores %v, want %v", scores, test.want)
+			}
+			for i := range scores {
+				if scores[i] != test.want[i] {
+					t.Fatalf("got scores %v, want %v", scores, test.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatInitials(t *testing.T) {
+	tests := []struct {
+		indices []int
+		want    string
+	}{
+		{[]int{0, 1, 2}, "A B C"},
+		{[]int{25, 26, 29}, "Z ! *"},
+		{[]int{}, ""},
+	}
+
+	for _, test := range tests {
+		if got := FormatInitials(test.indices); got != test.want {
+			t.Errorf("got initials %q for %v, want %q", got, test.indices, test.want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package systemd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Notification states understood by systemd, see sd_notify(3).
+const (
+	stateReady    = "READY=1"    // stateReady reports that startup is complete.
+	stateStopping = "STOPPING=1" // stateStopping reports that shutdown has begun.
+	stateWatchdog = "WATCHDOG=1" // stateWatchdog keeps the service watchdog from firing.
+)
+
+// notifier sends state notifications to the systemd notify socket.
+type notifier struct {
+	socket           string        // socket is the address of the notify socket, empty if not run with Type=notify.
+	watchdogInterval time.Duration // watchdogInterval is how often to pat the watchdog, zero if the watchdog is disabled.
+	lastWatchdog     time.Time     // lastWatchdog is when the watchdog was last patted.
+
+	m sync.Mutex
+}
+
+// theNotifier is the notifier for this process.
+var theNotifier = newNotifier()
+
+// newNotifier is a notifier factory, configured from the environment systemd provides.
+func newNotifier() *notifier {
+	n := &notifier{
+		socket: os.Getenv("NOTIFY_SOCKET"),
+	}
+
+	// Only pat the watchdog if it's enabled for this process
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return n
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return n
+	}
+
+	// Pat at twice the rate systemd requires, as sd_watchdog_enabled(3) recommends
+	n.watchdogInterval = time.Duration(usec) * time.Microsecond / 2
+
+	return n
+}
+
+// Notify sends a state notification to systemd.
+// Does nothing if the process wasn't started by systemd with Type=notify.
+func Notify(state string) error {
+	return theNotifier.notify(state)
+}
+
+// Ready notifies systemd that startup is complete.
+func Ready() error {
+	return Notify(stateReady)
+}
+
+// Status notifies systemd of a status line describing the current state of the machine.
+func Status(status string) error {
+	return Notify(fmt.Sprintf("STATUS=%s", status))
+}
+
+// Stopping notifies systemd that shutdown has begun.
+func Stopping() error {
+	return Notify(stateStopping)
+}
+
+// Watchdog pats the service watchdog if healthy and a pat is due.
+// Call regularly, e.g. every frame; withholding pats while unhealthy lets systemd restart the service.
+func Watchdog(healthy bool) error {
+	return theNotifier.watchdog(healthy, time.Now())
+}
+
+// notify sends a state notification to the notify socket, if any.
+func (n *notifier) notify(state string) error {
+	if n.socket == "" {
+		return nil
+	}
+
+	// Addresses starting with @ are abstract sockets, which net handles
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: n.socket, Net: "unixgram"})
+	if err != nil {
+		return fmt.Errorf("unable to dial notify socket: %s", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return fmt.Errorf("unable to write to notify socket: %s", err)
+	}
+	return nil
+}
+
+// watchdog pats the watchdog if healthy and at least watchdogInterval has passed since the last pat.
+func (n *notifier) watchdog(healthy bool, now time.Time) error {
+	if n.watchdogInterval == 0 || !healthy {
+		return nil
+	}
+
+	n.m.Lock()
+	if now.Sub(n.lastWatchdog) < n.watchdogInterval {
+		n.m.Unlock()
+		return nil
+	}
+	n.lastWatchdog = now
+	n.m.Unlock()
+
+	return n.notify(stateWatchdog)
+}
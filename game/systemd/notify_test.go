@@ -0,0 +1,129 @@
+package systemd
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// listenNotify listens on a fake notify socket, configuring the environment as systemd would.
+func listenNotify(t *testing.T, watchdogUSec string) *net.UnixConn {
+	socket := filepath.Join(t.TempDir(), "notify")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("unable to listen on notify socket: %s", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	setenv(t, "NOTIFY_SOCKET", socket)
+	setenv(t, "WATCHDOG_USEC", watchdogUSec)
+	setenv(t, "WATCHDOG_PID", strconv.Itoa(os.Getpid()))
+
+	return conn
+}
+
+// setenv sets an environment variable for the duration of a test.
+func setenv(t *testing.T, key, value string) {
+	previous, set := os.LookupEnv(key)
+	os.Setenv(key, value)
+	t.Cleanup(func() {
+		if set {
+			os.Setenv(key, previous)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+// received returns the notifications sent to the fake socket so far.
+func received(t *testing.T, conn *net.UnixConn) []string {
+	messages := []string{}
+	buf := make([]byte, 256)
+	for {
+		conn.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+		n, err := conn.Read(buf)
+		if err != nil {
+			return messages
+		}
+		messages = append(messages, string(buf[:n]))
+	}
+}
+
+func TestNotifySequence(t *testing.T) {
+	conn := listenNotify(t, "2000000")
+	n := newNotifier()
+	now := time.Now()
+
+	// Startup
+	steps := []func() error{
+		func() error { return n.notify(stateReady) },
+		func() error { return n.notify("STATUS=Menu") },
+		func() error { return n.watchdog(true, now) },
+		// Pats are rate limited to half of WATCHDOG_USEC
+		func() error { return n.watchdog(true, now.Add(500*time.Millisecond)) },
+		// A health failure withholds pats
+		func() error { return n.watchdog(false, now.Add(2*time.Second)) },
+		func() error { return n.notify("STATUS=Scoring") },
+		// Recovery resumes them
+		func() error { return n.watchdog(true, now.Add(3*time.Second)) },
+		// Shutdown
+		func() error { return n.notify(stateStopping) },
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("step %d: %s", i, err)
+		}
+	}
+
+	want := []string{"READY=1", "STATUS=Menu", "WATCHDOG=1", "STATUS=Scoring", "WATCHDOG=1", "STOPPING=1"}
+	got := received(t, conn)
+	if len(got) != len(want) {
+		t.Fatalf("got notifications %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got notifications %q, want %q", got, want)
+		}
+	}
+}
+
+func TestWatchdogDisabled(t *testing.T) {
+	tests := []struct {
+		name         string
+		watchdogUSec string
+		watchdogPID  string
+	}{
+		{"no watchdog", "", ""},
+		{"invalid interval", "soon", ""},
+		{"other process", "2000000", "1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn := listenNotify(t, test.watchdogUSec)
+			if test.watchdogPID != "" {
+				setenv(t, "WATCHDOG_PID", test.watchdogPID)
+			}
+
+			n := newNotifier()
+			if err := n.watchdog(true, time.Now()); err != nil {
+				t.Fatal(err)
+			}
+			if got := received(t, conn); len(got) != 0 {
+				t.Errorf("got notifications %q, want none", got)
+			}
+		})
+	}
+}
+
+func TestNotifyWithoutSocket(t *testing.T) {
+	setenv(t, "NOTIFY_SOCKET", "")
+	n := newNotifier()
+
+	if err := n.notify(stateReady); err != nil {
+		t.Errorf("got error %s without a notify socket, want none", err)
+	}
+}
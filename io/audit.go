@@ -0,0 +1,10 @@
+package io
+
+// Audit is a snapshot of resource usage, compared across a long session to spot leaks.
+type Audit struct {
+	Goroutines          int    // Goroutines is the number of goroutines in the process.
+	ClientRegistrations int    // ClientRegistrations is the number of pins the client considers registered.
+	PollerRegistrations int    // PollerRegistrations is the number of pins the poller is polling.
+	InFlightCallbacks   int    // InFlightCallbacks is the number of callbacks running or about to run.
+	HeapInUse           uint64 // HeapInUse is the bytes of heap in use after a forced garbage collection.
+}
@@ -0,0 +1,70 @@
+package io
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stianeikeland/go-rpio/v4"
+)
+
+// auditCycles is how many Poll/StopPolling cycles TestAuditFlatAcrossPollCycles runs.
+const auditCycles = 200
+
+// auditHeapEnvelope is how much the heap in use may grow across the cycles.
+const auditHeapEnvelope = 1 << 20
+
+func TestAuditFlatAcrossPollCycles(t *testing.T) {
+	pollFreq := time.Millisecond
+	r, _ := newSimulatedClient(pollFreq)
+	r.Poll()
+	for _, pin := range []rpio.Pin{2, 3, 4} {
+		if err := r.RegisterEdgeDetection(pin, rpio.RiseEdge, func(rpio.Edge) {}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var first Audit
+	for i := 0; i < auditCycles; i++ {
+		if i > 0 {
+			r.Poll()
+		}
+		audit, err := r.AuditSnapshot()
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.StopPolling()
+
+		if i == 0 {
+			first = audit
+		}
+		if audit.Goroutines > first.Goroutines {
+			t.Fatalf("got %d goroutines after %d cycles, want at most %d", audit.Goroutines, i, first.Goroutines)
+		}
+		if audit.ClientRegistrations != 3 || audit.PollerRegistrations != 3 {
+			t.Fatalf("got %d client and %d poller registrations after %d cycles, want 3", audit.ClientRegistrations, audit.PollerRegistrations, i)
+		}
+
+		// The stopped ticker no longer fires
+		select {
+		case <-r.poller.ticker.C:
+		default:
+		}
+		time.Sleep(3 * pollFreq)
+		select {
+		case <-r.poller.ticker.C:
+			t.Fatalf("got a tick after StopPolling in cycle %d, want the ticker stopped", i)
+		default:
+		}
+	}
+
+	last, err := r.AuditSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last.HeapInUse > first.HeapInUse+auditHeapEnvelope {
+		t.Errorf("got heap in use growing from %d to %d bytes, want at most %d bytes growth", first.HeapInUse, last.HeapInUse, auditHeapEnvelope)
+	}
+	if last.Goroutines >= first.Goroutines {
+		t.Errorf("got %d goroutines once stopped, want fewer than the %d while polling", last.Goroutines, first.Goroutines)
+	}
+}
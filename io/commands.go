@@ -0,0 +1,128 @@
+package io
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/stianeikeland/go-rpio/v4"
+)
+
+// commandTimeout is how long a command without a caller-provided context may take to be applied.
+const commandTimeout = time.Second
+
+// commandQueueSize is how many commands may be waiting for the poll goroutine.
+const commandQueueSize = 16
+
+// command is an operation executed by the poll goroutine between ticks.
+type command interface {
+	// apply executes the command against the poller.
+	apply(p *rpioPoller)
+}
+
+// queuedCommand is a command waiting in the poller's command queue.
+type queuedCommand struct {
+	command command       // command is the operation to execute.
+	applied chan struct{} // applied is closed once the command has been executed.
+}
+
+// submit queues a command for the poll goroutine and waits until it has been applied.
+// Commands are applied in the order their submission is accepted by the queue.
+// Returns the context's error if it is done before the command is applied,
+// in which case a command that was already queued may still be applied later.
+func (p *rpioPoller) submit(ctx context.Context, c command) error {
+	// Don't queue a command for a context that is already done
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	queued := queuedCommand{
+		command: c,
+		applied: make(chan struct{}),
+	}
+
+	select {
+	case p.commands <- queued:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-queued.applied:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// submitWithTimeout submits a command, giving up after commandTimeout.
+func (p *rpioPoller) submitWithTimeout(c command) error {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	return p.submit(ctx, c)
+}
+
+// addPinCommand adds a pin registration to polling.
+type addPinCommand struct {
+	registration pinRegistration // registration is the registration to poll.
+}
+
+// apply executes the command against the poller.
+func (c *addPinCommand) apply(p *rpioPoller) {
+	p.registeredPins[c.registration.pin] = c.registration
+}
+
+// removePinCommand removes a pin registration from polling.
+// Once applied, the poller no longer dispatches edges for the pin.
+type removePinCommand struct {
+	pin rpio.Pin // pin is the pin to stop polling.
+}
+
+// apply executes the command against the poller.
+func (c *removePinCommand) apply(p *rpioPoller) {
+	delete(p.registeredPins, c.pin)
+}
+
+// pollFreqCommand updates the polling frequency.
+type pollFreqCommand struct {
+	pollFreq time.Duration // pollFreq is the new polling period.
+}
+
+// apply executes the command against the poller.
+func (c *pollFreqCommand) apply(p *rpioPoller) {
+	p.pollFreq = c.pollFreq
+	p.ticker.Reset(c.pollFreq)
+}
+
+// barrierCommand closes out the set of edges dispatched so far.
+type barrierCommand struct {
+	dispatched *sync.WaitGroup // dispatched tracks the callbacks of edges dispatched before the barrier, set when applied.
+}
+
+// apply executes the command against the poller.
+func (c *barrierCommand) apply(p *rpioPoller) {
+	// Pick up edges latched since the last tick before closing out the set
+	p.detectEdges(time.Now())
+	c.dispatched = p.dispatched
+	p.dispatched = &sync.WaitGroup{}
+}
+
+// snapshotCommand reads the poller's view of registrations.
+type snapshotCommand struct {
+	registrations int // registrations is the number of pins being polled, set when applied.
+}
+
+// apply executes the command against the poller.
+func (c *snapshotCommand) apply(p *rpioPoller) {
+	c.registrations = len(p.registeredPins)
+}
+
+// stopCommand ends polling, the poll goroutine returning once it is applied.
+type stopCommand struct{}
+
+// apply executes the command against the poller.
+func (c *stopCommand) apply(p *rpioPoller) {
+	// Release the ticker, a new one is created when polling resumes
+	p.ticker.Stop()
+}
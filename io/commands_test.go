@@ -0,0 +1,168 @@
+package io
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stianeikeland/go-rpio/v4"
+)
+
+// recordCommand records the order commands are applied in.
+type recordCommand struct {
+	id      int           // id identifies the command.
+	applied *[]int        // applied contains the ids of commands applied so far.
+	delay   time.Duration // delay is how long applying the command takes.
+}
+
+// apply executes the command against the poller.
+func (c *recordCommand) apply(p *rpioPoller) {
+	time.Sleep(c.delay)
+	*c.applied = append(*c.applied, c.id)
+}
+
+// queueCommands submits commands from their own goroutines, waiting for each to be accepted before the next.
+// Returns a channel receiving each submission's error.
+func queueCommands(t *testing.T, p *rpioPoller, commands []command) <-chan error {
+	errs := make(chan error, len(commands))
+	for i, c := range commands {
+		go func(c command) {
+			errs <- p.submitWithTimeout(c)
+		}(c)
+
+		deadline := time.Now().Add(time.Second)
+		for len(p.commands) < i+1 {
+			if time.Now().After(deadline) {
+				t.Fatalf("got %d commands queued, want %d", len(p.commands), i+1)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	return errs
+}
+
+// waitSubmitted waits for all of the submissions to succeed.
+func waitSubmitted(t *testing.T, errs <-chan error, n int) {
+	for i := 0; i < n; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("unable to submit command: %s", err)
+		}
+	}
+}
+
+func TestCommandsApplyInAcceptedOrder(t *testing.T) {
+	r, _ := newSimulatedClient(time.Hour)
+
+	// Queue a full set of commands before the poll goroutine drains any
+	applied := []int{}
+	commands := []command{}
+	for i := 0; i < commandQueueSize; i++ {
+		commands = append(commands, &recordCommand{id: i, applied: &applied})
+	}
+	errs := queueCommands(t, r.poller, commands)
+
+	r.Poll()
+	defer r.StopPolling()
+	waitSubmitted(t, errs, len(commands))
+
+	if len(applied) != len(commands) {
+		t.Fatalf("got %d commands applied, want %d", len(applied), len(commands))
+	}
+	for i, id := range applied {
+		if id != i {
+			t.Fatalf("got commands applied in order %v, want the order accepted", applied)
+		}
+	}
+}
+
+func TestSubmitWithTimeoutUndrained(t *testing.T) {
+	r, _ := newSimulatedClient(time.Hour)
+
+	// Nothing drains the queue as polling hasn't started
+	start := time.Now()
+	err := r.poller.submitWithTimeout(&snapshotCommand{})
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v submitting to an undrained queue, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed < commandTimeout || elapsed > commandTimeout+500*time.Millisecond {
+		t.Errorf("got submission failing after %s, want %s", elapsed, commandTimeout)
+	}
+}
+
+func TestCommandBurstDoesNotDelayTicks(t *testing.T) {
+	pollFreq := 20 * time.Millisecond
+	commandDelay := 15 * time.Millisecond
+	r, _ := newSimulatedClient(pollFreq)
+
+	// Record when each tick reads the pins
+	ticks := []time.Time{}
+	m := &sync.Mutex{}
+	registerCounter(r.poller, 0)
+	r.poller.edgeDetected = func(rpio.Pin) bool {
+		m.Lock()
+		defer m.Unlock()
+
+		ticks = append(ticks, time.Now())
+		return false
+	}
+
+	// Fill the queue with slow commands, taking far longer than a tick in total
+	applied := []int{}
+	commands := []command{}
+	for i := 0; i < commandQueueSize; i++ {
+		commands = append(commands, &recordCommand{id: i, applied: &applied, delay: commandDelay})
+	}
+	errs := queueCommands(t, r.poller, commands)
+
+	start := time.Now()
+	r.Poll()
+	defer r.StopPolling()
+	waitSubmitted(t, errs, len(commands))
+	end := time.Now()
+
+	// Ticks keep coming while the burst is applied, at most a command later than due
+	m.Lock()
+	defer m.Unlock()
+	maxGap := pollFreq + commandDelay + 30*time.Millisecond
+	last := start
+	for _, tick := range append(ticks, end) {
+		if tick.After(end) {
+			tick = end
+		}
+		if gap := tick.Sub(last); gap > maxGap {
+			t.Fatalf("got %s without a tick during a burst of %d commands, want at most %s", gap, len(commands), maxGap)
+		}
+		last = tick
+	}
+}
+
+func TestSlowPollsDoNotStarveCommands(t *testing.T) {
+	pollFreq := time.Millisecond
+	r, _ := newSimulatedClient(pollFreq)
+	polls := new(int32)
+
+	// Every poll takes longer than the poll period, so a tick is always pending
+	registerCounter(r.poller, 0)
+	r.poller.edgeDetected = func(rpio.Pin) bool {
+		atomic.AddInt32(polls, 1)
+		time.Sleep(2 * pollFreq)
+		return false
+	}
+
+	r.Poll()
+	defer r.StopPolling()
+	for atomic.LoadInt32(polls) < 2 {
+		time.Sleep(pollFreq)
+	}
+
+	for i := 0; i < commandQueueSize; i++ {
+		if err := r.poller.submitWithTimeout(&snapshotCommand{}); err != nil {
+			t.Fatalf("got error %v submitting command %d while polls overrun, want none", err, i)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package io
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/stianeikeland/go-rpio/v4"
+)
+
+// Client is the GPIO interface backing the package-level functions.
+type Client interface {
+	Start()
+	Poll()
+	StopPolling()
+	Stop()
+	RegisterEdgeDetection(pin rpio.Pin, edge rpio.Edge, callback func(rpio.Edge), opts ...RegistrationOption) error
+	RemoveEdgeDetectionRegistration(pin rpio.Pin) error
+	RemoveAndWait(pin rpio.Pin) error
+	UpdatePollFreq(d time.Duration) error
+	Quiesce(ctx context.Context) error
+	LatencyStats(pin rpio.Pin) (RegistrationLatency, error)
+	PollerStatus() PollerHealth
+	AuditSnapshot() (Audit, error)
+	WriteSet(states map[rpio.Pin]rpio.State) error
+}
+
+// defaultClient is the client the package-level functions delegate to.
+var defaultClient Client
+
+// defaultClientMutex guards defaultClient.
+var defaultClientMutex sync.RWMutex
+
+// SetDefault sets the client the package-level functions delegate to.
+func SetDefault(c Client) {
+	defaultClientMutex.Lock()
+	defer defaultClientMutex.Unlock()
+
+	defaultClient = c
+}
+
+// Default returns the client the package-level functions delegate to.
+// Until SetDefault is called this is RPIOClient.
+func Default() Client {
+	defaultClientMutex.RLock()
+	c := defaultClient
+	defaultClientMutex.RUnlock()
+	if c != nil {
+		return c
+	}
+
+	defaultClientMutex.Lock()
+	defer defaultClientMutex.Unlock()
+
+	// Another caller may have set the default in the meantime
+	if defaultClient == nil {
+		defaultClient = RPIOClient
+	}
+
+	return defaultClient
+}
+
+// Start opens GPIO on the default client.
+func Start() {
+	Default().Start()
+}
+
+// Poll starts polling on the default client.
+func Poll() {
+	Default().Poll()
+}
+
+// StopPolling stops polling on the default client.
+func StopPolling() {
+	Default().StopPolling()
+}
+
+// Stop closes GPIO on the default client.
+func Stop() {
+	Default().Stop()
+}
+
+// RegisterEdgeDetection registers a callback for a detected edge on a specified pin with the default client.
+func RegisterEdgeDetection(pin rpio.Pin, edge rpio.Edge, callback func(rpio.Edge), opts ...RegistrationOption) error {
+	return Default().RegisterEdgeDetection(pin, edge, callback, opts...)
+}
+
+// RemoveEdgeDetectionRegistration removes an edge detection registration for a specified pin from the default client.
+func RemoveEdgeDetectionRegistration(pin rpio.Pin) error {
+	return Default().RemoveEdgeDetectionRegistration(pin)
+}
+
+// RemoveAndWait removes an edge detection registration from the default client and waits for its dispatch goroutines to exit.
+func RemoveAndWait(pin rpio.Pin) error {
+	return Default().RemoveAndWait(pin)
+}
+
+// UpdatePollFreq changes the polling frequency of the default client.
+func UpdatePollFreq(d time.Duration) error {
+	return Default().UpdatePollFreq(d)
+}
+
+// Quiesce waits for dispatched callbacks on the default client to complete.
+// See rPIO.Quiesce for when it may block forever.
+func Quiesce(ctx context.Context) error {
+	return Default().Quiesce(ctx)
+}
+
+// LatencyStats returns the callback latencies of a registered pin on the default client.
+func LatencyStats(pin rpio.Pin) (RegistrationLatency, error) {
+	return Default().LatencyStats(pin)
+}
+
+// PollerStatus reports the health of the default client's poll goroutine.
+func PollerStatus() PollerHealth {
+	return Default().PollerStatus()
+}
+
+// AuditSnapshot reports resource usage of the default client.
+func AuditSnapshot() (Audit, error) {
+	return Default().AuditSnapshot()
+}
+
+// WriteSet sets the states of several output pins together on the default client.
+func WriteSet(states map[rpio.Pin]rpio.State) error {
+	return Default().WriteSet(states)
+}
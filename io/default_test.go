@@ -0,0 +1,73 @@
+package io
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stianeikeland/go-rpio/v4"
+)
+
+// useDefault sets the default client for the duration of a test.
+func useDefault(t *testing.T, c Client) {
+	SetDefault(c)
+	t.Cleanup(func() { SetDefault(nil) })
+}
+
+func TestDefaultFallsBackToRPIOClient(t *testing.T) {
+	useDefault(t, nil)
+
+	// Concurrent first use settles on the singleton
+	clients := make(chan Client, 8)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < cap(clients); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			clients <- Default()
+		}()
+	}
+	wg.Wait()
+	close(clients)
+
+	for c := range clients {
+		if c != Client(RPIOClient) {
+			t.Fatalf("got default client %p, want RPIOClient %p", c, RPIOClient)
+		}
+	}
+}
+
+func TestPackageFunctionsDelegate(t *testing.T) {
+	r, _ := newTestClient(t)
+	bank := &recordingBank{}
+	r.bank = bank
+	useDefault(t, r)
+
+	if err := RegisterEdgeDetection(testPin, rpio.RiseEdge, func(rpio.Edge) {}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.registeredPins[testPin]; !ok {
+		t.Fatalf("got no registration on the default client, want pin %d", testPin)
+	}
+
+	if err := UpdatePollFreq(time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if r.poller.pollFreq != time.Minute {
+		t.Errorf("got poll frequency %s on the default client, want %s", r.poller.pollFreq, time.Minute)
+	}
+
+	if err := WriteSet(map[rpio.Pin]rpio.State{testPin: rpio.High}); err != nil {
+		t.Fatal(err)
+	}
+	if len(bank.operations) != 1 {
+		t.Errorf("got %d bank operations on the default client, want 1", len(bank.operations))
+	}
+
+	if err := RemoveAndWait(testPin); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.registeredPins[testPin]; ok {
+		t.Errorf("got pin %d still registered on the default client, want it removed", testPin)
+	}
+}
@@ -0,0 +1,171 @@
+package io
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/stianeikeland/go-rpio/v4"
+)
+
+// edgeEvent is a detected edge awaiting its callback.
+type edgeEvent struct {
+	edge     rpio.Edge       // edge is the edge passed to the callback.
+	detected time.Time       // detected is the time of the poll that detected the edge.
+	done     *sync.WaitGroup // done is marked once the callback completes or the event is discarded.
+}
+
+// dispatcher runs a registration's callback for detected edges, honoring its concurrency limit.
+type dispatcher struct {
+	pin      rpio.Pin            // pin is the registered pin, used in log lines.
+	callback func(rpio.Edge)     // callback is the function to run when an edge is detected.
+	options  registrationOptions // options are the registration's dispatch settings.
+	inFlight int                 // inFlight is the number of goroutines running or about to run callbacks.
+	running  int                 // running is the number of callbacks let through the closed check and not yet returned.
+	pending  []edgeEvent         // pending contains events waiting for a callback to complete.
+	latency  RegistrationLatency // latency records dispatch delay and execution time of callbacks.
+	closed   bool                // closed is set once the registration is removed, after which no callback starts.
+
+	m    sync.Mutex
+	idle *sync.Cond // idle is signaled when inFlight or running drops to zero.
+}
+
+// newDispatcher is a dispatcher factory.
+func newDispatcher(pin rpio.Pin, callback func(rpio.Edge), options registrationOptions) *dispatcher {
+	d := &dispatcher{
+		pin:      pin,
+		callback: callback,
+		options:  options,
+	}
+	d.idle = sync.NewCond(&d.m)
+	return d
+}
+
+// dispatch runs the callback for an event, or holds the event per the overflow policy if at the concurrency limit.
+func (d *dispatcher) dispatch(event edgeEvent) {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	if d.closed {
+		event.done.Done()
+		return
+	}
+
+	if d.options.maxConcurrency == 0 || d.inFlight < d.options.maxConcurrency {
+		d.inFlight++
+		go d.run(event)
+		return
+	}
+
+	switch d.options.overflowPolicy {
+	case OverflowQueue:
+		// Drop the event if the queue is full
+		if len(d.pending) >= MaxQueuedEdges {
+			event.done.Done()
+			return
+		}
+		d.pending = append(d.pending, event)
+	case OverflowCoalesce:
+		// Latest event wins
+		for _, discarded := range d.pending {
+			discarded.done.Done()
+		}
+		d.pending = append(d.pending[:0], event)
+	}
+}
+
+// run executes the callback, then continues with pending events until none remain or the dispatcher is closed.
+func (d *dispatcher) run(event edgeEvent) {
+	for {
+		// Never start a callback once the registration is removed,
+		// counting it as running under the same lock so close waits for it
+		d.m.Lock()
+		if d.closed {
+			d.inFlight--
+			if d.inFlight == 0 {
+				d.idle.Broadcast()
+			}
+			d.m.Unlock()
+			event.done.Done()
+			return
+		}
+		d.running++
+		d.m.Unlock()
+
+		started := time.Now()
+		delay := started.Sub(event.detected)
+		if d.options.slowDispatchThreshold > 0 && delay > d.options.slowDispatchThreshold {
+			log.Printf("slow dispatch on pin %d: callback started %s after edge detection", d.pin, delay)
+		}
+
+		d.callback(event.edge)
+		event.done.Done()
+		execution := time.Since(started)
+
+		d.m.Lock()
+		d.running--
+		if d.running == 0 {
+			d.idle.Broadcast()
+		}
+		d.latency.DispatchDelay.record(delay)
+		d.latency.Execution.record(execution)
+
+		if len(d.pending) == 0 {
+			d.inFlight--
+			if d.inFlight == 0 {
+				d.idle.Broadcast()
+			}
+			d.m.Unlock()
+			return
+		}
+
+		// Take the oldest pending event
+		event = d.pending[0]
+		d.pending = d.pending[1:]
+		d.m.Unlock()
+	}
+}
+
+// close discards pending events and guarantees no callback starts after it returns.
+// A callback let through before closing can't be told apart from one partway through running,
+// so close waits for those to return. Must not be called from within the callback.
+func (d *dispatcher) close() {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	d.closed = true
+	for _, discarded := range d.pending {
+		discarded.done.Done()
+	}
+	d.pending = nil
+
+	for d.running > 0 {
+		d.idle.Wait()
+	}
+}
+
+// wait blocks until no goroutines are running or about to run callbacks.
+func (d *dispatcher) wait() {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	for d.inFlight > 0 {
+		d.idle.Wait()
+	}
+}
+
+// inFlightCallbacks returns the number of goroutines running or about to run callbacks.
+func (d *dispatcher) inFlightCallbacks() int {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	return d.inFlight
+}
+
+// latencyStats returns a copy of the recorded latencies.
+func (d *dispatcher) latencyStats() RegistrationLatency {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	return d.latency
+}
@@ -0,0 +1,188 @@
+package io
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stianeikeland/go-rpio/v4"
+)
+
+// floodEvents is how many events floodDispatcher dispatches at most.
+const floodEvents = 256
+
+// floodDispatcher dispatches up to floodEvents events to d from a goroutine, stopping early if stop is closed.
+func floodDispatcher(d *dispatcher, done *sync.WaitGroup, stop chan struct{}) *sync.WaitGroup {
+	flooding := &sync.WaitGroup{}
+	flooding.Add(1)
+	go func() {
+		defer flooding.Done()
+		for i := 0; i < floodEvents; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			done.Add(1)
+			d.dispatch(edgeEvent{edge: rpio.RiseEdge, detected: time.Now(), done: done})
+		}
+	}()
+	return flooding
+}
+
+func TestDispatcherCloseRacingFlood(t *testing.T) {
+	// The race needs callbacks and close running in parallel
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	tests := []struct {
+		name    string
+		options registrationOptions
+	}{
+		{"unlimited", registrationOptions{}},
+		{"queue", registrationOptions{maxConcurrency: 1, overflowPolicy: OverflowQueue}},
+		{"coalesce", registrationOptions{maxConcurrency: 2, overflowPolicy: OverflowCoalesce}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for trial := 0; trial < 2000; trial++ {
+				var started, closed, late int32
+				d := newDispatcher(1, func(rpio.Edge) {
+					if atomic.LoadInt32(&closed) == 1 {
+						atomic.AddInt32(&late, 1)
+					}
+					atomic.AddInt32(&started, 1)
+				}, test.options)
+
+				done := &sync.WaitGroup{}
+				stop := make(chan struct{})
+				flooding := floodDispatcher(d, done, stop)
+
+				// Close while callbacks are running
+				for atomic.LoadInt32(&started) == 0 {
+					runtime.Gosched()
+				}
+				d.close()
+				atomic.StoreInt32(&closed, 1)
+
+				close(stop)
+				flooding.Wait()
+				d.wait()
+				done.Wait()
+
+				if late > 0 {
+					t.Fatalf("trial %d: %d callbacks started after close returned", trial, late)
+				}
+			}
+		})
+	}
+}
+
+// blockingCallback records edges, blocking each callback until release is closed.
+type blockingCallback struct {
+	entered chan struct{} // entered receives a value as each callback starts.
+	release chan struct{} // release unblocks callbacks once closed.
+	edges   []rpio.Edge   // edges are the edges callbacks were run with, in order.
+
+	m sync.Mutex
+}
+
+// newBlockingCallback is a blockingCallback factory.
+func newBlockingCallback() *blockingCallback {
+	return &blockingCallback{
+		entered: make(chan struct{}, floodEvents),
+		release: make(chan struct{}),
+	}
+}
+
+// callback is the registered callback.
+func (b *blockingCallback) callback(edge rpio.Edge) {
+	b.m.Lock()
+	b.edges = append(b.edges, edge)
+	b.m.Unlock()
+
+	b.entered <- struct{}{}
+	<-b.release
+}
+
+func TestDispatcherOverflow(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   OverflowPolicy
+		overflow []rpio.Edge
+		want     []rpio.Edge
+	}{
+		{
+			name:     "queue in order",
+			policy:   OverflowQueue,
+			overflow: []rpio.Edge{rpio.FallEdge, rpio.RiseEdge, rpio.AnyEdge},
+			want:     []rpio.Edge{rpio.RiseEdge, rpio.FallEdge, rpio.RiseEdge, rpio.AnyEdge},
+		},
+		{
+			name:     "coalesce to latest",
+			policy:   OverflowCoalesce,
+			overflow: []rpio.Edge{rpio.FallEdge, rpio.RiseEdge, rpio.AnyEdge},
+			want:     []rpio.Edge{rpio.RiseEdge, rpio.AnyEdge},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := newBlockingCallback()
+			d := newDispatcher(testPin, b.callback, registrationOptions{maxConcurrency: 1, overflowPolicy: test.policy})
+			done := &sync.WaitGroup{}
+
+			// Occupy the only callback slot, then overflow it
+			done.Add(1)
+			d.dispatch(edgeEvent{edge: rpio.RiseEdge, detected: time.Now(), done: done})
+			<-b.entered
+			for _, edge := range test.overflow {
+				done.Add(1)
+				d.dispatch(edgeEvent{edge: edge, detected: time.Now(), done: done})
+			}
+
+			if inFlight := d.inFlightCallbacks(); inFlight != 1 {
+				t.Errorf("got %d callbacks in flight at the limit, want 1", inFlight)
+			}
+
+			close(b.release)
+			done.Wait()
+			d.wait()
+
+			if len(b.edges) != len(test.want) {
+				t.Fatalf("got edges %v, want %v", b.edges, test.want)
+			}
+			for i := range test.want {
+				if b.edges[i] != test.want[i] {
+					t.Fatalf("got edges %v, want %v", b.edges, test.want)
+				}
+			}
+		})
+	}
+}
+
+func TestDispatcherQueueDropsWhenFull(t *testing.T) {
+	b := newBlockingCallback()
+	d := newDispatcher(testPin, b.callback, registrationOptions{maxConcurrency: 1, overflowPolicy: OverflowQueue})
+	done := &sync.WaitGroup{}
+
+	// Occupy the only callback slot, then dispatch more than the queue holds
+	done.Add(1)
+	d.dispatch(edgeEvent{edge: rpio.RiseEdge, detected: time.Now(), done: done})
+	<-b.entered
+	for i := 0; i < MaxQueuedEdges+5; i++ {
+		done.Add(1)
+		d.dispatch(edgeEvent{edge: rpio.FallEdge, detected: time.Now(), done: done})
+	}
+
+	// Dropped events must still be marked done
+	close(b.release)
+	done.Wait()
+	d.wait()
+
+	if want := 1 + MaxQueuedEdges; len(b.edges) != want {
+		t.Errorf("got %d callbacks, want %d", len(b.edges), want)
+	}
+}
@@ -0,0 +1,59 @@
+//go:build linux
+// +build linux
+
+package io
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// GPIO register word offsets within /dev/gpiomem.
+const (
+	gpset0     = 0x1c / 4
+	gpclr0     = 0x28 / 4
+	gpioMemLen = 4096
+)
+
+// gpioMem is a gpioBank backed by a mapping of /dev/gpiomem.
+type gpioMem struct {
+	mem8 []byte   // mem8 is the mapped register block.
+	mem  []uint32 // mem is mem8 addressed by register word.
+}
+
+// openGPIOBank maps the GPIO registers for bank writes.
+func openGPIOBank() (gpioBank, error) {
+	file, err := os.OpenFile("/dev/gpiomem", os.O_RDWR|os.O_SYNC, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	mem8, err := syscall.Mmap(int(file.Fd()), 0, gpioMemLen, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gpioMem{
+		mem8: mem8,
+		mem:  (*[gpioMemLen / 4]uint32)(unsafe.Pointer(&mem8[0]))[:],
+	}, nil
+}
+
+func (g *gpioMem) setClear(set, clear [2]uint32) {
+	for bank := range set {
+		if set[bank] != 0 {
+			g.mem[gpset0+bank] = set[bank]
+		}
+	}
+	for bank := range clear {
+		if clear[bank] != 0 {
+			g.mem[gpclr0+bank] = clear[bank]
+		}
+	}
+}
+
+func (g *gpioMem) close() error {
+	return syscall.Munmap(g.mem8)
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+// +build !linux
+
+package io
+
+import "fmt"
+
+// openGPIOBank reports that bank writes are unsupported off Linux.
+func openGPIOBank() (gpioBank, error) {
+	return nil, fmt.Errorf("GPIO registers can only be mapped on linux")
+}
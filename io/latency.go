@@ -0,0 +1,52 @@
+package io
+
+import "time"
+
+// LatencyBuckets are the upper bounds of the LatencyHistogram buckets.
+var LatencyBuckets = [...]time.Duration{
+	100 * time.Microsecond,
+	500 * time.Microsecond,
+	1 * time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+}
+
+// LatencyHistogram is a distribution of durations over LatencyBuckets.
+type LatencyHistogram struct {
+	Counts [len(LatencyBuckets) + 1]uint64 // Counts holds a count per bucket, the last bucket counts durations beyond every bound.
+	Total  uint64                          // Total is the number of recorded durations.
+	Sum    time.Duration                   // Sum is the sum of recorded durations.
+	Max    time.Duration                   // Max is the longest recorded duration.
+}
+
+// record adds a duration to the histogram.
+func (h *LatencyHistogram) record(d time.Duration) {
+	i := 0
+	for i < len(LatencyBuckets) && d > LatencyBuckets[i] {
+		i++
+	}
+	h.Counts[i]++
+	h.Total++
+	h.Sum += d
+	if d > h.Max {
+		h.Max = d
+	}
+}
+
+// Mean returns the average recorded duration.
+func (h LatencyHistogram) Mean() time.Duration {
+	if h.Total == 0 {
+		return 0
+	}
+	return h.Sum / time.Duration(h.Total)
+}
+
+// RegistrationLatency are the processing latencies of a registration's edges.
+type RegistrationLatency struct {
+	DispatchDelay LatencyHistogram // DispatchDelay is the time from the poll detecting an edge to its callback starting.
+	Execution     LatencyHistogram // Execution is the time callbacks took to run.
+}
@@ -0,0 +1,88 @@
+package io
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stianeikeland/go-rpio/v4"
+)
+
+func TestLatencyHistogramRecord(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration time.Duration
+		bucket   int
+	}{
+		{"zero", 0, 0},
+		{"on first bound", 100 * time.Microsecond, 0},
+		{"above first bound", 101 * time.Microsecond, 1},
+		{"middle", 7 * time.Millisecond, 4},
+		{"on last bound", time.Second, len(LatencyBuckets) - 1},
+		{"beyond every bound", time.Minute, len(LatencyBuckets)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var h LatencyHistogram
+			h.record(test.duration)
+
+			for i, count := range h.Counts {
+				want := uint64(0)
+				if i == test.bucket {
+					want = 1
+				}
+				if count != want {
+					t.Errorf("got count %d in bucket %d, want %d", count, i, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLatencyHistogramTotals(t *testing.T) {
+	var h LatencyHistogram
+	if h.Mean() != 0 {
+		t.Errorf("got mean %s of empty histogram, want 0", h.Mean())
+	}
+
+	for _, d := range []time.Duration{time.Millisecond, 3 * time.Millisecond, 8 * time.Millisecond} {
+		h.record(d)
+	}
+
+	if h.Total != 3 {
+		t.Errorf("got total %d, want 3", h.Total)
+	}
+	if h.Sum != 12*time.Millisecond {
+		t.Errorf("got sum %s, want 12ms", h.Sum)
+	}
+	if h.Max != 8*time.Millisecond {
+		t.Errorf("got max %s, want 8ms", h.Max)
+	}
+	if h.Mean() != 4*time.Millisecond {
+		t.Errorf("got mean %s, want 4ms", h.Mean())
+	}
+}
+
+func TestDispatcherRecordsDispatchDelay(t *testing.T) {
+	d := newDispatcher(testPin, func(rpio.Edge) {}, registrationOptions{})
+	done := &sync.WaitGroup{}
+
+	// Dispatch an edge detected 20ms ago
+	delay := 20 * time.Millisecond
+	done.Add(1)
+	d.dispatch(edgeEvent{edge: rpio.RiseEdge, detected: time.Now().Add(-delay), done: done})
+	done.Wait()
+	d.wait()
+
+	stats := d.latencyStats()
+	if stats.DispatchDelay.Total != 1 || stats.Execution.Total != 1 {
+		t.Fatalf("got %d dispatch delays and %d executions recorded, want 1 each", stats.DispatchDelay.Total, stats.Execution.Total)
+	}
+	if stats.DispatchDelay.Max < delay {
+		t.Errorf("got dispatch delay %s, want at least %s", stats.DispatchDelay.Max, delay)
+	}
+	if stats.DispatchDelay.Counts[5] != 1 {
+		t.Errorf("got dispatch delay buckets %v, want the delay in the 50ms bucket", stats.DispatchDelay.Counts)
+	}
+}
@@ -0,0 +1,89 @@
+package io
+
+import (
+	"fmt"
+	"time"
+)
+
+// OverflowPolicy determines how edges are handled when a registration is running its maximum number of callbacks.
+type OverflowPolicy int
+
+// Enumeration of overflow policies.
+const (
+	// OverflowQueue holds excess edges, up to MaxQueuedEdges, and runs them in order as callbacks complete.
+	OverflowQueue OverflowPolicy = iota
+	// OverflowCoalesce holds only the latest excess edge, discarding any edge already waiting.
+	OverflowCoalesce
+)
+
+// MaxQueuedEdges is the most edges a registration will hold under OverflowQueue before dropping new ones.
+const MaxQueuedEdges = 16
+
+// registrationOptions are the configurable parameters of an edge detection registration.
+type registrationOptions struct {
+	maxConcurrency        int            // maxConcurrency is the maximum number of concurrently running callbacks, 0 is unlimited.
+	overflowPolicy        OverflowPolicy // overflowPolicy determines how edges beyond maxConcurrency are handled.
+	slowDispatchThreshold time.Duration  // slowDispatchThreshold is the dispatch delay beyond which a log line is written, 0 disables it.
+	debounce              time.Duration  // debounce is the window after an accepted edge in which further edges are ignored, 0 disables it.
+}
+
+// RegistrationOption configures an edge detection registration.
+type RegistrationOption func(*registrationOptions)
+
+// WithMaxConcurrency limits how many callbacks of a registration may run at once.
+// A value of 0 or less places no limit, which is the default.
+func WithMaxConcurrency(n int) RegistrationOption {
+	return func(o *registrationOptions) {
+		o.maxConcurrency = n
+	}
+}
+
+// WithOverflowPolicy sets how edges are handled when a registration is running its maximum number of callbacks.
+// Only meaningful in combination with WithMaxConcurrency. Defaults to OverflowQueue.
+func WithOverflowPolicy(policy OverflowPolicy) RegistrationOption {
+	return func(o *registrationOptions) {
+		o.overflowPolicy = policy
+	}
+}
+
+// WithSlowDispatchThreshold logs a line whenever a callback starts more than d after its edge was detected.
+// A value of 0 or less disables the log line, which is the default.
+func WithSlowDispatchThreshold(d time.Duration) RegistrationOption {
+	return func(o *registrationOptions) {
+		o.slowDispatchThreshold = d
+	}
+}
+
+// WithDebounce ignores edges detected within d of the previously accepted edge.
+// Edges are suppressed by the poller, before any callback is dispatched, so callbacks needn't be thread-safe to benefit.
+// Edges are timestamped by poll, so d is effectively rounded up to a multiple of the poll frequency.
+// A value of 0 or less disables debouncing, which is the default.
+func WithDebounce(d time.Duration) RegistrationOption {
+	return func(o *registrationOptions) {
+		o.debounce = d
+	}
+}
+
+// newRegistrationOptions applies options over the defaults and validates the result.
+func newRegistrationOptions(opts []RegistrationOption) (registrationOptions, error) {
+	o := registrationOptions{
+		overflowPolicy: OverflowQueue,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.maxConcurrency < 0 {
+		o.maxConcurrency = 0
+	}
+
+	if o.debounce < 0 {
+		o.debounce = 0
+	}
+
+	if o.overflowPolicy != OverflowQueue && o.overflowPolicy != OverflowCoalesce {
+		return o, fmt.Errorf("unknown overflow policy %d", o.overflowPolicy)
+	}
+
+	return o, nil
+}
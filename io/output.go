@@ -0,0 +1,76 @@
+package io
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/stianeikeland/go-rpio/v4"
+)
+
+// maxPin is the highest BCM pin addressable through the set/clear registers.
+const maxPin = 53
+
+// gpioBank writes the BCM GPSET and GPCLR registers directly.
+type gpioBank interface {
+	// setClear sets the pins in set and clears the pins in clear, one register write each per bank in use.
+	setClear(set, clear [2]uint32)
+	// close releases the register mapping.
+	close() error
+}
+
+// bankMasks returns the per-bank GPSET and GPCLR masks applying states.
+func bankMasks(states map[rpio.Pin]rpio.State) (set, clear [2]uint32, err error) {
+	for pin, state := range states {
+		if pin > maxPin {
+			return set, clear, fmt.Errorf("pin %d is out of range", pin)
+		}
+
+		bank, bit := pin/32, uint32(1)<<(pin%32)
+		if state == rpio.High {
+			set[bank] |= bit
+		} else {
+			clear[bank] |= bit
+		}
+	}
+
+	return set, clear, nil
+}
+
+// WriteSet sets the states of several output pins together.
+//
+// When the GPIO registers are mapped the pins change in at most one GPSET and one GPCLR write per bank, pins 0-31
+// forming the first bank. External hardware can still sample between the set and clear writes, so hardware latching
+// a multi-bit code should be strobed after WriteSet returns.
+//
+// Without bank write support pins are instead written one at a time in ascending pin order, meaning external hardware
+// sampling the pins mid-update may observe any intermediate combination.
+func (r *rPIO) WriteSet(states map[rpio.Pin]rpio.State) error {
+	if !r.open {
+		return fmt.Errorf("GPIO is not yet open")
+	}
+
+	set, clear, err := bankMasks(states)
+	if err != nil {
+		return fmt.Errorf("unable to write pins: %s", err)
+	}
+
+	if r.bank != nil {
+		r.bank.setClear(set, clear)
+		return nil
+	}
+
+	// Write in a deterministic order
+	pins := make([]rpio.Pin, 0, len(states))
+	for pin := range states {
+		pins = append(pins, pin)
+	}
+	sort.Slice(pins, func(i, j int) bool {
+		return pins[i] < pins[j]
+	})
+
+	for _, pin := range pins {
+		pin.Write(states[pin])
+	}
+
+	return nil
+}
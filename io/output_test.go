@@ -0,0 +1,87 @@
+package io
+
+import (
+	"testing"
+
+	"github.com/stianeikeland/go-rpio/v4"
+)
+
+// recordingBank is a gpioBank recording the bank operations it receives.
+type recordingBank struct {
+	operations [][2][2]uint32 // operations are the set and clear masks of each operation.
+}
+
+func (b *recordingBank) setClear(set, clear [2]uint32) {
+	b.operations = append(b.operations, [2][2]uint32{set, clear})
+}
+
+func (b *recordingBank) close() error {
+	return nil
+}
+
+func TestWriteSetBank(t *testing.T) {
+	tests := []struct {
+		name   string
+		states map[rpio.Pin]rpio.State
+		set    [2]uint32
+		clear  [2]uint32
+	}{
+		{
+			name:   "sound code",
+			states: map[rpio.Pin]rpio.State{5: rpio.High, 6: rpio.Low, 13: rpio.High},
+			set:    [2]uint32{1<<5 | 1<<13, 0},
+			clear:  [2]uint32{1 << 6, 0},
+		},
+		{
+			name:   "all low",
+			states: map[rpio.Pin]rpio.State{0: rpio.Low, 31: rpio.Low},
+			clear:  [2]uint32{1<<0 | 1<<31, 0},
+		},
+		{
+			name:   "second bank",
+			states: map[rpio.Pin]rpio.State{32: rpio.High, 53: rpio.Low, 2: rpio.High},
+			set:    [2]uint32{1 << 2, 1 << 0},
+			clear:  [2]uint32{0, 1 << 21},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, _ := newSimulatedClient(DefaultPollFreq)
+			bank := &recordingBank{}
+			r.bank = bank
+
+			if err := r.WriteSet(test.states); err != nil {
+				t.Fatal(err)
+			}
+			if len(bank.operations) != 1 {
+				t.Fatalf("got %d bank operations, want 1", len(bank.operations))
+			}
+			if got := bank.operations[0][0]; got != test.set {
+				t.Errorf("got set masks %#x, want %#x", got, test.set)
+			}
+			if got := bank.operations[0][1]; got != test.clear {
+				t.Errorf("got clear masks %#x, want %#x", got, test.clear)
+			}
+		})
+	}
+}
+
+func TestWriteSetErrors(t *testing.T) {
+	r, _ := newSimulatedClient(DefaultPollFreq)
+	bank := &recordingBank{}
+	r.bank = bank
+
+	if err := r.WriteSet(map[rpio.Pin]rpio.State{4: rpio.High, 54: rpio.High}); err == nil {
+		t.Error("got no error writing pin 54, want one")
+	}
+
+	r.open = false
+	if err := r.WriteSet(map[rpio.Pin]rpio.State{4: rpio.High}); err == nil {
+		t.Error("got no error writing before Start, want one")
+	}
+
+	if len(bank.operations) != 0 {
+		t.Errorf("got %d bank operations after failed writes, want 0", len(bank.operations))
+	}
+}
@@ -1,7 +1,11 @@
 package io
 
 import (
+	"context"
 	"fmt"
+	"log"
+	"runtime"
+	"sync"
 	"time"
 
 	"github.com/stianeikeland/go-rpio/v4"
@@ -15,27 +19,26 @@ const DefaultPollFreq = 100 * time.Millisecond
 
 func init() {
 	// Instatiate RPIO client singleton
-	RPIOClient = &rPIO{
-		open:    false,
-		polling: false,
-		poller: &rpioPoller{
-			ticker:         time.NewTicker(DefaultPollFreq),
-			registeredPins: make(map[rpio.Pin]pinRegistration),
-			newPin:         make(chan pinRegistration),
-			removePin:      make(chan rpio.Pin),
-			newPollFreq:    make(chan time.Duration),
-			stop:           make(chan struct{}),
-		},
-		registeredPins: make(map[rpio.Pin]bool),
+	RPIOClient = newRPIO()
+}
+
+// newRPIO is a rPIO factory.
+func newRPIO() *rPIO {
+	return &rPIO{
+		open:           false,
+		polling:        false,
+		poller:         newRPIOPoller(),
+		registeredPins: make(map[rpio.Pin]*dispatcher),
 	}
 }
 
 // rPIO is a wrapper interfacing with Raspberry Pi GPIO.
 type rPIO struct {
-	open           bool              // open maintains state of GPIO.
-	polling        bool              // polling maintains state of polling.
-	poller         *rpioPoller       // poller manages polling pins for edge detection.
-	registeredPins map[rpio.Pin]bool // registeredPins keeps track of what pins are registered.
+	open           bool                     // open maintains state of GPIO.
+	polling        bool                     // polling maintains state of polling.
+	poller         *rpioPoller              // poller manages polling pins for edge detection.
+	registeredPins map[rpio.Pin]*dispatcher // registeredPins keeps track of what pins are registered, and their dispatchers.
+	bank           gpioBank                 // bank writes output pins in bulk, nil without bank write support.
 }
 
 // Start opens the GPIO pins and starts polling.
@@ -51,6 +54,12 @@ func (r *rPIO) Start() {
 		panic(fmt.Sprintf("unable to open GPIO: %s", err))
 	}
 
+	// Map the set/clear registers, falling back to sequential writes
+	r.bank, err = openGPIOBank()
+	if err != nil {
+		log.Printf("bank writes unavailable, writing output pins sequentially: %s", err)
+	}
+
 	r.open = true
 }
 
@@ -61,8 +70,10 @@ func (r *rPIO) Poll() {
 		return
 	}
 
-	// Start polling
-	go r.poller.poll()
+	// Start polling, restarting the poll goroutine if it dies
+	r.poller.ticker = time.NewTicker(r.poller.pollFreq)
+	r.poller.done = make(chan struct{})
+	go r.poller.supervise()
 
 	r.polling = true
 }
@@ -75,7 +86,13 @@ func (r *rPIO) StopPolling() {
 	}
 
 	// Signal polling goroutine to stop
-	r.poller.stop <- struct{}{}
+	err := r.poller.submitWithTimeout(&stopCommand{})
+	if err != nil {
+		panic(fmt.Sprintf("unable to stop polling: %s", err))
+	}
+
+	// Wait for the polling goroutine to exit so a new one can't overlap it
+	<-r.poller.done
 
 	r.polling = false
 }
@@ -92,6 +109,15 @@ func (r *rPIO) Stop() {
 		r.StopPolling()
 	}
 
+	// Unmap the set/clear registers
+	if r.bank != nil {
+		err := r.bank.close()
+		if err != nil {
+			panic(fmt.Sprintf("unable to unmap GPIO registers: %s", err))
+		}
+		r.bank = nil
+	}
+
 	// Close GPIO
 	err := rpio.Close()
 	if err != nil {
@@ -103,7 +129,8 @@ func (r *rPIO) Stop() {
 
 // RegisterEdgeDetection registers a callback for a detected edge on a specified pin.
 // Requires rPIO.Poll() to be called in order to detect events.
-func (r *rPIO) RegisterEdgeDetection(pin rpio.Pin, edge rpio.Edge, callback func(rpio.Edge)) error {
+// Options such as WithMaxConcurrency tune how the callback is run.
+func (r *rPIO) RegisterEdgeDetection(pin rpio.Pin, edge rpio.Edge, callback func(rpio.Edge), opts ...RegistrationOption) error {
 	if !r.open {
 		return fmt.Errorf("GPIO is not yet open")
 	}
@@ -117,101 +144,282 @@ func (r *rPIO) RegisterEdgeDetection(pin rpio.Pin, edge rpio.Edge, callback func
 		return fmt.Errorf("pin is already registered, call RemoveEdgeDetectionRegistration before attempting a new registration")
 	}
 
+	options, err := newRegistrationOptions(opts)
+	if err != nil {
+		return fmt.Errorf("invalid registration options: %s", err)
+	}
+
 	// Only one registration per pin
-	r.registeredPins[pin] = true
+	d := newDispatcher(pin, callback, options)
+	r.registeredPins[pin] = d
 
 	// Setup detection
-	pin.Detect(edge)
+	r.poller.detect(pin, edge)
 
 	// Register with poller
-	r.poller.newPin <- pinRegistration{
-		pin:      pin,
-		edge:     edge,
-		callback: callback,
+	err = r.poller.submitWithTimeout(&addPinCommand{
+		registration: pinRegistration{
+			pin:        pin,
+			edge:       edge,
+			debounce:   options.debounce,
+			dispatcher: d,
+		},
+	})
+	if err != nil {
+		// Undo the registration, the poller may still apply the command later so close the dispatcher too
+		delete(r.registeredPins, pin)
+		r.poller.detect(pin, rpio.NoEdge)
+		d.close()
+		return fmt.Errorf("unable to register pin with poller: %s", err)
 	}
 
 	return nil
 }
 
 // RemoveEdgeDetectionRegistration removes an edge detection registration for a specified pin.
+// Once it returns, the pin's callback is not running and no new invocation of it will start.
+// Must not be called from within the pin's own callback.
 func (r *rPIO) RemoveEdgeDetectionRegistration(pin rpio.Pin) error {
+	_, err := r.removeEdgeDetectionRegistration(pin)
+	return err
+}
+
+// RemoveAndWait removes an edge detection registration for a specified pin,
+// then also waits for the goroutines that would have run the pin's callback to exit.
+// Must not be called from within the pin's own callback.
+func (r *rPIO) RemoveAndWait(pin rpio.Pin) error {
+	d, err := r.removeEdgeDetectionRegistration(pin)
+	if err != nil {
+		return err
+	}
+
+	// Wait for running callbacks
+	d.wait()
+
+	return nil
+}
+
+// removeEdgeDetectionRegistration removes a registration from the poller and closes its dispatcher.
+func (r *rPIO) removeEdgeDetectionRegistration(pin rpio.Pin) (*dispatcher, error) {
 	if !r.open {
-		return fmt.Errorf("GPIO is not yet open")
+		return nil, fmt.Errorf("GPIO is not yet open")
 	}
 
 	if !r.polling {
-		return fmt.Errorf("not yet polling GPIO")
+		return nil, fmt.Errorf("not yet polling GPIO")
 	}
 
-	_, exists := r.registeredPins[pin]
+	d, exists := r.registeredPins[pin]
 	if !exists {
-		return fmt.Errorf("pin is not yet registered")
+		return nil, fmt.Errorf("pin is not yet registered")
 	}
 
 	// Remove pin registration
 	delete(r.registeredPins, pin)
 
 	// Clear detection
-	pin.Detect(rpio.NoEdge)
+	r.poller.detect(pin, rpio.NoEdge)
 
-	// Remove registration with poller
-	r.poller.removePin <- pin
+	// Remove registration with poller, waiting for acknowledgement that it will dispatch no more edges
+	err := r.poller.submitWithTimeout(&removePinCommand{pin: pin})
 
-	return nil
+	// Prevent edges already dispatched from starting callbacks
+	d.close()
+
+	if err != nil {
+		return d, fmt.Errorf("unable to remove pin from poller: %s", err)
+	}
+
+	return d, nil
+}
+
+// LatencyStats returns the dispatch delay and execution time distributions of a registered pin's callbacks.
+func (r *rPIO) LatencyStats(pin rpio.Pin) (RegistrationLatency, error) {
+	d, exists := r.registeredPins[pin]
+	if !exists {
+		return RegistrationLatency{}, fmt.Errorf("pin is not yet registered")
+	}
+
+	return d.latencyStats(), nil
 }
 
 // UpdatePollFreq changes the polling frequency of edge detection.
 func (r *rPIO) UpdatePollFreq(d time.Duration) error {
 	if !r.open {
-		return fmt.Errorf("polling has not yet started")
+		return fmt.Errorf("GPIO is not yet open")
+	}
+
+	if !r.polling {
+		return fmt.Errorf("not yet polling GPIO")
+	}
+
+	if d <= 0 {
+		return fmt.Errorf("poll frequency must be positive, got %s", d)
 	}
 
 	// Update the poller frequency
-	r.poller.newPollFreq <- d
+	err := r.poller.submitWithTimeout(&pollFreqCommand{pollFreq: d})
+	if err != nil {
+		return fmt.Errorf("unable to update poll frequency: %s", err)
+	}
 
 	return nil
 }
 
+// PollerStatus reports how often the poll goroutine has died and been restarted.
+func (r *rPIO) PollerStatus() PollerHealth {
+	return r.poller.supervisor.status()
+}
+
+// AuditSnapshot reports resource usage for spotting leaks over long sessions.
+// Forces a garbage collection so heap usage reflects live memory.
+func (r *rPIO) AuditSnapshot() (Audit, error) {
+	audit := Audit{
+		ClientRegistrations: len(r.registeredPins),
+	}
+
+	// Count in-flight callbacks across registrations
+	for _, d := range r.registeredPins {
+		audit.InFlightCallbacks += d.inFlightCallbacks()
+	}
+
+	// The poller's registrations may only be read by the poll goroutine while polling
+	if r.polling {
+		snapshot := &snapshotCommand{}
+		err := r.poller.submitWithTimeout(snapshot)
+		if err != nil {
+			return audit, fmt.Errorf("unable to snapshot poller: %s", err)
+		}
+		audit.PollerRegistrations = snapshot.registrations
+	} else {
+		audit.PollerRegistrations = len(r.poller.registeredPins)
+	}
+
+	// Measure the runtime last so the snapshot itself is accounted for
+	runtime.GC()
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	audit.HeapInUse = memStats.HeapInuse
+	audit.Goroutines = runtime.NumGoroutine()
+
+	return audit, nil
+}
+
+// Quiesce blocks until the callbacks for every edge detected before the call have completed.
+// Edges latched by the hardware but not yet polled are read immediately, so they are included.
+// Returns the context's error if it is done first.
+//
+// Must not be called from within an edge callback, as it would wait on that same callback and deadlock.
+// The barrier is only applied by a running poll goroutine, so if polling is stopped before then, or the poll goroutine
+// dies while applying it, Quiesce blocks until the context is done. With a context that is never done it blocks forever.
+func (r *rPIO) Quiesce(ctx context.Context) error {
+	if !r.open {
+		return fmt.Errorf("GPIO is not yet open")
+	}
+
+	if !r.polling {
+		return fmt.Errorf("not yet polling GPIO")
+	}
+
+	// Send a barrier through the poller, which closes out the edges dispatched before it
+	barrier := &barrierCommand{}
+	err := r.poller.submit(ctx, barrier)
+	if err != nil {
+		return err
+	}
+
+	// Wait for those edges' callbacks to complete
+	done := make(chan struct{})
+	go func() {
+		barrier.dispatched.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // pinRegistration is a registration for a callback when an edge is detected for a pin.
 type pinRegistration struct {
-	pin      rpio.Pin        // pin is the pin to monitor for edge detection.
-	edge     rpio.Edge       // edge is the type of edge to run the callback on.
-	callback func(rpio.Edge) // callback is the function to run when an edge is detected.
+	pin          rpio.Pin      // pin is the pin to monitor for edge detection.
+	edge         rpio.Edge     // edge is the type of edge to run the callback on.
+	debounce     time.Duration // debounce is the window after an accepted edge in which further edges are ignored.
+	lastAccepted time.Time     // lastAccepted is the poll time of the last edge passed to the dispatcher.
+	dispatcher   *dispatcher   // dispatcher runs the callback when an edge is detected.
 }
 
 // rpioPoller manages polling pins for edge detection.
 type rpioPoller struct {
-	ticker         *time.Ticker                 // ticker manages the polling period.
+	ticker         *time.Ticker                 // ticker manages the polling period, only running while polling.
+	pollFreq       time.Duration                // pollFreq is the polling period.
 	registeredPins map[rpio.Pin]pinRegistration // registeredPins contains which pins should be polled for what edge detection.
-	newPin         chan pinRegistration         // newPins allows a new pin to be incorporated into polling.
-	removePin      chan rpio.Pin                // removePin allows a pin to be removed from polling.
-	newPollFreq    chan time.Duration           // newPollFreq updates the polling frequency.
-	stop           chan struct{}                // stop ends polling.
+	commands       chan queuedCommand           // commands queues operations for the poll goroutine, applied in order.
+	dispatched     *sync.WaitGroup              // dispatched tracks callbacks for edges detected since the last barrier.
+	done           chan struct{}                // done is closed once the supervised poll goroutine has exited.
+	supervisor     *pollerSupervisor            // supervisor tracks restarts of the poll goroutine.
+	detect         func(rpio.Pin, rpio.Edge)    // detect arms a pin's hardware edge detection, replaceable to simulate edges.
+	edgeDetected   func(rpio.Pin) bool          // edgeDetected reads and clears a pin's latched edge, replaceable to simulate edges.
 }
 
-// poll starts the pin polling routine.
+// newRPIOPoller is a rpioPoller factory.
+func newRPIOPoller() *rpioPoller {
+	return &rpioPoller{
+		pollFreq:       DefaultPollFreq,
+		registeredPins: make(map[rpio.Pin]pinRegistration),
+		commands:       make(chan queuedCommand, commandQueueSize),
+		dispatched:     &sync.WaitGroup{},
+		supervisor:     &pollerSupervisor{},
+		detect:         rpio.Pin.Detect,
+		edgeDetected:   rpio.Pin.EdgeDetected,
+	}
+}
+
+// poll starts the pin polling routine, returning once a stop command is applied.
 func (p *rpioPoller) poll() {
-pollLoop:
 	for {
+		// Handle a pending tick before any further commands so a burst of commands can't delay it.
+		// Then wait on both, so polls overrunning the poll period can't starve commands either.
+		select {
+		case tick := <-p.ticker.C:
+			p.detectEdges(tick)
+		default:
+		}
+
 		select {
-		case <-p.ticker.C:
-			// Read pins and handle edge detection
-			for pin, registration := range p.registeredPins {
-				if pin.EdgeDetected() {
-					go registration.callback(registration.edge)
-				}
+		case tick := <-p.ticker.C:
+			p.detectEdges(tick)
+		case queued := <-p.commands:
+			queued.command.apply(p)
+			close(queued.applied)
+
+			if _, stop := queued.command.(*stopCommand); stop {
+				return
+			}
+		}
+	}
+}
+
+// detectEdges reads pins and dispatches callbacks for detected edges, stamped with the poll time.
+func (p *rpioPoller) detectEdges(polled time.Time) {
+	for pin, registration := range p.registeredPins {
+		if p.edgeDetected(pin) {
+			// Suppress edges within the debounce window of the last accepted edge
+			if registration.debounce > 0 && !registration.lastAccepted.IsZero() && polled.Sub(registration.lastAccepted) < registration.debounce {
+				continue
 			}
-		case newRegistration := <-p.newPin:
-			// Add pin registration to pins to poll
-			p.registeredPins[newRegistration.pin] = newRegistration
-		case registrationToRemove := <-p.removePin:
-			// Remove pin registration from pins to poll
-			delete(p.registeredPins, registrationToRemove)
-		case newPollFreq := <-p.newPollFreq:
-			// Update the ticker polling frequency
-			p.ticker.Reset(newPollFreq)
-		case <-p.stop:
-			break pollLoop
+			registration.lastAccepted = polled
+			p.registeredPins[pin] = registration
+
+			p.dispatched.Add(1)
+			registration.dispatcher.dispatch(edgeEvent{
+				edge:     registration.edge,
+				detected: polled,
+				done:     p.dispatched,
+			})
 		}
 	}
 }
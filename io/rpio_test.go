@@ -0,0 +1,242 @@
+package io
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stianeikeland/go-rpio/v4"
+)
+
+// testPin is the pin simulated edges are detected on.
+const testPin = rpio.Pin(17)
+
+// newTestPoller returns a poller on which every poll detects an edge on every registered pin.
+func newTestPoller() *rpioPoller {
+	p := newRPIOPoller()
+	p.edgeDetected = func(rpio.Pin) bool { return true }
+	return p
+}
+
+// registerCounter registers testPin with the poller, returning a count of callbacks run.
+func registerCounter(p *rpioPoller, debounce time.Duration) *int32 {
+	count := new(int32)
+	d := newDispatcher(testPin, func(rpio.Edge) { atomic.AddInt32(count, 1) }, registrationOptions{})
+	(&addPinCommand{registration: pinRegistration{
+		pin:        testPin,
+		edge:       rpio.RiseEdge,
+		debounce:   debounce,
+		dispatcher: d,
+	}}).apply(p)
+	return count
+}
+
+// pollAt polls the poller at each offset from start, then waits for callbacks to complete.
+func pollAt(p *rpioPoller, start time.Time, offsets ...time.Duration) {
+	for _, offset := range offsets {
+		p.detectEdges(start.Add(offset))
+	}
+	p.dispatched.Wait()
+}
+
+func TestDetectEdgesDebounce(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name     string
+		debounce time.Duration
+		offsets  []time.Duration
+		want     int32
+	}{
+		{"one callback per window", 50 * ms, []time.Duration{0, 10 * ms, 20 * ms, 49 * ms, 50 * ms, 60 * ms, 99 * ms, 100 * ms}, 3},
+		{"rapid edges within window", 50 * ms, []time.Duration{0, 1 * ms, 2 * ms, 3 * ms, 4 * ms}, 1},
+		{"no debounce", 0, []time.Duration{0, 1 * ms, 2 * ms, 3 * ms, 4 * ms}, 5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := newTestPoller()
+			count := registerCounter(p, test.debounce)
+
+			pollAt(p, time.Now(), test.offsets...)
+
+			if got := atomic.LoadInt32(count); got != test.want {
+				t.Errorf("got %d callbacks, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDetectEdgesDebounceSurvivesPollFreqChange(t *testing.T) {
+	p := newTestPoller()
+	p.ticker = time.NewTicker(DefaultPollFreq)
+	defer p.ticker.Stop()
+	count := registerCounter(p, 50*time.Millisecond)
+
+	start := time.Now()
+	pollAt(p, start, 0)
+	(&pollFreqCommand{pollFreq: 10 * time.Millisecond}).apply(p)
+	pollAt(p, start, 10*time.Millisecond)
+
+	if got := atomic.LoadInt32(count); got != 1 {
+		t.Errorf("got %d callbacks, want 1 as the edge after the change is within the window", got)
+	}
+}
+
+func TestDetectEdgesDebounceClearedByReregistration(t *testing.T) {
+	p := newTestPoller()
+	first := registerCounter(p, 50*time.Millisecond)
+
+	start := time.Now()
+	pollAt(p, start, 0)
+	(&removePinCommand{pin: testPin}).apply(p)
+	second := registerCounter(p, 50*time.Millisecond)
+	pollAt(p, start, 10*time.Millisecond)
+
+	if got := atomic.LoadInt32(first); got != 1 {
+		t.Errorf("got %d callbacks for the first registration, want 1", got)
+	}
+	if got := atomic.LoadInt32(second); got != 1 {
+		t.Errorf("got %d callbacks for the new registration, want 1 as it starts without a window", got)
+	}
+}
+
+// simulatedPins latches edges in place of GPIO edge detection.
+type simulatedPins struct {
+	latched map[rpio.Pin]bool // latched contains the pins with an edge awaiting a poll.
+
+	m sync.Mutex
+}
+
+// latch simulates the hardware detecting an edge on a pin.
+func (s *simulatedPins) latch(pin rpio.Pin) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.latched[pin] = true
+}
+
+// edgeDetected reads and clears a pin's latched edge.
+func (s *simulatedPins) edgeDetected(pin rpio.Pin) bool {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	detected := s.latched[pin]
+	delete(s.latched, pin)
+	return detected
+}
+
+// newSimulatedClient returns an open client backed by simulated pins, not yet polling.
+func newSimulatedClient(pollFreq time.Duration) (*rPIO, *simulatedPins) {
+	pins := &simulatedPins{latched: map[rpio.Pin]bool{}}
+
+	r := newRPIO()
+	r.open = true
+	r.poller.pollFreq = pollFreq
+	r.poller.detect = func(rpio.Pin, rpio.Edge) {}
+	r.poller.edgeDetected = pins.edgeDetected
+
+	return r, pins
+}
+
+// newTestClient returns a polling client backed by simulated pins.
+// Ticks are an hour apart so edges are only read when a test asks for them.
+func newTestClient(t *testing.T) (*rPIO, *simulatedPins) {
+	r, pins := newSimulatedClient(time.Hour)
+	r.Poll()
+	t.Cleanup(r.StopPolling)
+
+	return r, pins
+}
+
+func TestQuiesceWaitsForSlowCallback(t *testing.T) {
+	tests := []struct {
+		name    string
+		quiesce bool
+		want    int32
+	}{
+		{"score included with quiesce", true, 100},
+		{"score missed without quiesce", false, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, pins := newTestClient(t)
+
+			var score int32
+			started := make(chan struct{}, 1)
+			err := r.RegisterEdgeDetection(testPin, rpio.RiseEdge, func(rpio.Edge) {
+				started <- struct{}{}
+				time.Sleep(50 * time.Millisecond)
+				atomic.AddInt32(&score, 100)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// A hit lands just before game over
+			pins.latch(testPin)
+			if test.quiesce {
+				if err := r.Quiesce(context.Background()); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				// Read the edge without waiting for its callback
+				if err := r.poller.submitWithTimeout(&barrierCommand{}); err != nil {
+					t.Fatal(err)
+				}
+				<-started
+			}
+
+			// Record the game over score
+			if got := atomic.LoadInt32(&score); got != test.want {
+				t.Errorf("got score %d at game over, want %d", got, test.want)
+			}
+
+			if err := r.RemoveAndWait(testPin); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestQuiesceContextExpiry(t *testing.T) {
+	r, pins := newTestClient(t)
+
+	release := make(chan struct{})
+	err := r.RegisterEdgeDetection(testPin, rpio.RiseEdge, func(rpio.Edge) {
+		<-release
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pins.latch(testPin)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := r.Quiesce(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v from Quiesce with a blocked callback, want %v", err, context.DeadlineExceeded)
+	}
+
+	close(release)
+	if err := r.Quiesce(context.Background()); err != nil {
+		t.Errorf("got error %v from Quiesce once the callback completed, want none", err)
+	}
+	if err := r.RemoveAndWait(testPin); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestQuiesceCanceledContext(t *testing.T) {
+	r, _ := newTestClient(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 200; i++ {
+		if err := r.Quiesce(ctx); !errors.Is(err, context.Canceled) {
+			t.Fatalf("got error %v from Quiesce with a canceled context, want %v", err, context.Canceled)
+		}
+	}
+}
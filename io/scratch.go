@@ -9,10 +9,10 @@ import (
 
 func TestRPIO() {
 	// Start RPIO
-	RPIOClient.Start()
-	RPIOClient.Poll()
-	defer RPIOClient.StopPolling()
-	defer RPIOClient.Stop()
+	Start()
+	Poll()
+	defer StopPolling()
+	defer Stop()
 
 	pinNames := map[rpio.Pin]string{
 		2:  "Pluto",
@@ -35,7 +35,7 @@ func TestRPIO() {
 		pin.Input()
 		pin.PullUp()
 
-		RPIOClient.RegisterEdgeDetection(pin, rpio.AnyEdge, generateCallback(pinNumber, name))
+		RegisterEdgeDetection(pin, rpio.AnyEdge, generateCallback(pinNumber, name))
 	}
 
 	stop := time.After(60 * time.Second)
@@ -0,0 +1,177 @@
+package io
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"math/rand"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stianeikeland/go-rpio/v4"
+)
+
+// stressDuration runs TestStress for a duration rather than a fixed number of operations, e.g. -stress=10m.
+var stressDuration = flag.Duration("stress", 0, "run TestStress for this long instead of a fixed number of operations")
+
+// stressSeed seeds TestStress's operation sequence, 0 picks one from the time.
+var stressSeed = flag.Int64("stress.seed", 0, "seed for TestStress, 0 seeds from the time")
+
+// stressOps is how many operations TestStress runs by default.
+const stressOps = 3000
+
+// stressPins are the pins TestStress registers.
+var stressPins = []rpio.Pin{2, 3, 4, 17}
+
+// stressDeadline is the longest StopPolling, removal or Quiesce may take.
+const stressDeadline = 2 * time.Second
+
+// stressTest drives a client on simulated pins with random operations, checking invariants after each one.
+type stressTest struct {
+	t         *testing.T
+	rand      *rand.Rand
+	r         *rPIO
+	pins      *simulatedPins
+	latched   int64 // latched is how many edges have been simulated.
+	callbacks int64 // callbacks is how many callbacks have started.
+}
+
+func TestStress(t *testing.T) {
+	seed := *stressSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	t.Logf("seed %d", seed)
+
+	goroutines := runtime.NumGoroutine()
+
+	r, pins := newSimulatedClient(time.Millisecond)
+	s := &stressTest{
+		t:    t,
+		rand: rand.New(rand.NewSource(seed)),
+		r:    r,
+		pins: pins,
+	}
+
+	// Run operations until done
+	end := time.Now().Add(*stressDuration)
+	for i := 0; (*stressDuration == 0 && i < stressOps) || (*stressDuration > 0 && time.Now().Before(end)); i++ {
+		s.step()
+		s.check()
+	}
+
+	// Tear down and check nothing is left running
+	if !r.polling {
+		r.Poll()
+	}
+	for _, pin := range stressPins {
+		if _, exists := r.registeredPins[pin]; exists {
+			s.withinDeadline("RemoveAndWait", func() { r.RemoveAndWait(pin) })
+		}
+	}
+	s.withinDeadline("StopPolling", r.StopPolling)
+
+	deadline := time.Now().Add(stressDeadline)
+	for runtime.NumGoroutine() > goroutines {
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d goroutines after teardown, want at most %d", runtime.NumGoroutine(), goroutines)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// step runs a random operation.
+func (s *stressTest) step() {
+	pin := stressPins[s.rand.Intn(len(stressPins))]
+
+	switch s.rand.Intn(8) {
+	case 0, 1:
+		atomic.AddInt64(&s.latched, 1)
+		s.pins.latch(pin)
+	case 2:
+		// Registration fails if not polling or already registered
+		sleep := time.Duration(s.rand.Intn(200)) * time.Microsecond
+		s.r.RegisterEdgeDetection(pin, rpio.RiseEdge, func(rpio.Edge) {
+			atomic.AddInt64(&s.callbacks, 1)
+			time.Sleep(sleep)
+		}, s.randomOptions()...)
+	case 3:
+		if s.rand.Intn(2) == 0 {
+			s.withinDeadline("RemoveEdgeDetectionRegistration", func() { s.r.RemoveEdgeDetectionRegistration(pin) })
+		} else {
+			s.withinDeadline("RemoveAndWait", func() { s.r.RemoveAndWait(pin) })
+		}
+	case 4:
+		// Include invalid frequencies, which must be rejected
+		s.r.UpdatePollFreq(time.Duration(s.rand.Intn(3000)-500) * time.Microsecond)
+	case 5:
+		if s.r.polling {
+			s.withinDeadline("StopPolling", s.r.StopPolling)
+		} else {
+			s.r.Poll()
+		}
+	case 6:
+		ctx, cancel := context.WithTimeout(context.Background(), stressDeadline)
+		err := s.r.Quiesce(ctx)
+		cancel()
+		if errors.Is(err, context.DeadlineExceeded) {
+			s.t.Fatalf("Quiesce did not complete within %s", stressDeadline)
+		}
+	case 7:
+		// Many commands back to back
+		for i := 0; i < commandQueueSize*2; i++ {
+			s.r.UpdatePollFreq(time.Duration(1+s.rand.Intn(3)) * time.Millisecond)
+		}
+	}
+}
+
+// randomOptions returns random registration options.
+func (s *stressTest) randomOptions() []RegistrationOption {
+	opts := []RegistrationOption{}
+	if s.rand.Intn(2) == 0 {
+		opts = append(opts, WithMaxConcurrency(1+s.rand.Intn(2)))
+	}
+	if s.rand.Intn(2) == 0 {
+		opts = append(opts, WithOverflowPolicy(OverflowCoalesce))
+	}
+	if s.rand.Intn(2) == 0 {
+		opts = append(opts, WithDebounce(time.Duration(s.rand.Intn(5))*time.Millisecond))
+	}
+	return opts
+}
+
+// check verifies invariants between operations.
+func (s *stressTest) check() {
+	audit, err := s.r.AuditSnapshot()
+	if err != nil {
+		s.t.Fatalf("unable to audit: %s", err)
+	}
+	if audit.ClientRegistrations != audit.PollerRegistrations {
+		s.t.Fatalf("got %d client registrations and %d poller registrations, want them equal", audit.ClientRegistrations, audit.PollerRegistrations)
+	}
+
+	if callbacks, latched := atomic.LoadInt64(&s.callbacks), atomic.LoadInt64(&s.latched); callbacks > latched {
+		s.t.Fatalf("got %d callbacks for %d edges, want at most one per edge", callbacks, latched)
+	}
+
+	if status := s.r.PollerStatus(); status.Restarts > 0 {
+		s.t.Fatalf("got %d poller restarts, want none", status.Restarts)
+	}
+}
+
+// withinDeadline fails the test if f doesn't return within stressDeadline.
+func (s *stressTest) withinDeadline(name string, f func()) {
+	done := make(chan struct{})
+	go func() {
+		f()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(stressDeadline):
+		s.t.Fatalf("%s did not return within %s", name, stressDeadline)
+	}
+}
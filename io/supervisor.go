@@ -0,0 +1,100 @@
+package io
+
+import (
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// Poller supervision constants
+const (
+	pollerRestartBackoff = 100 * time.Millisecond // pollerRestartBackoff is how long to wait before restarting a dead poll goroutine.
+	pollerMaxRestarts    = 3                      // pollerMaxRestarts is how many restarts within pollerRestartWindow are tolerated as healthy.
+	pollerRestartWindow  = 5 * time.Minute        // pollerRestartWindow is the period over which restarts are counted for health.
+)
+
+// PollerHealth reports the health of the poll goroutine.
+type PollerHealth struct {
+	Restarts    int       // Restarts is how many times the poll goroutine has been restarted after dying.
+	LastRestart time.Time // LastRestart is when the poll goroutine was last restarted, zero if never.
+	Healthy     bool      // Healthy is false if more than pollerMaxRestarts restarts happened within pollerRestartWindow.
+}
+
+// pollerSupervisor tracks restarts of the poll goroutine.
+type pollerSupervisor struct {
+	restarts     int         // restarts is the total number of restarts.
+	restartTimes []time.Time // restartTimes contains the most recent restart times, oldest first.
+
+	m sync.Mutex
+}
+
+// supervise runs the poll routine, restarting it with the current registrations if it dies.
+// Closes done once polling is stopped.
+func (p *rpioPoller) supervise() {
+	defer close(p.done)
+
+	for {
+		if p.runPoll() {
+			// Polling was stopped on request
+			return
+		}
+
+		p.supervisor.recordRestart(time.Now())
+		time.Sleep(pollerRestartBackoff)
+
+		// Re-arm hardware detection and clear any events latched while the poller was down
+		for pin, registration := range p.registeredPins {
+			p.detect(pin, registration.edge)
+			p.edgeDetected(pin)
+		}
+	}
+}
+
+// runPoll runs the poll routine, returning false if it died from a panic.
+func (p *rpioPoller) runPoll() (stopped bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("poll goroutine died, restarting: %v\n%s", r, debug.Stack())
+			stopped = false
+		}
+	}()
+
+	p.poll()
+
+	return true
+}
+
+// recordRestart notes that the poll goroutine was restarted.
+func (s *pollerSupervisor) recordRestart(t time.Time) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.restarts++
+	s.restartTimes = append(s.restartTimes, t)
+	if len(s.restartTimes) > pollerMaxRestarts+1 {
+		s.restartTimes = s.restartTimes[1:]
+	}
+}
+
+// status reports the health of the poll goroutine.
+func (s *pollerSupervisor) status() PollerHealth {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	status := PollerHealth{
+		Restarts: s.restarts,
+		Healthy:  true,
+	}
+
+	if len(s.restartTimes) > 0 {
+		status.LastRestart = s.restartTimes[len(s.restartTimes)-1]
+	}
+
+	// Unhealthy if too many restarts happened recently
+	if len(s.restartTimes) > pollerMaxRestarts && time.Since(s.restartTimes[0]) < pollerRestartWindow {
+		status.Healthy = false
+	}
+
+	return status
+}
@@ -0,0 +1,86 @@
+package io
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stianeikeland/go-rpio/v4"
+)
+
+// panicCommand kills the poll goroutine when applied.
+type panicCommand struct{}
+
+// apply executes the command against the poller.
+func (c *panicCommand) apply(p *rpioPoller) {
+	panic("injected poller failure")
+}
+
+// injectPanic kills the poll goroutine, waiting until the supervisor has restarted it the given total of times.
+func injectPanic(t *testing.T, r *rPIO, restarts int) {
+	// The command is never marked applied, so its submission times out
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	r.poller.submit(ctx, &panicCommand{})
+
+	deadline := time.Now().Add(time.Second)
+	for r.PollerStatus().Restarts < restarts {
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d restarts, want %d", r.PollerStatus().Restarts, restarts)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// Wait for the restarted goroutine to accept commands
+	if err := r.poller.submitWithTimeout(&snapshotCommand{}); err != nil {
+		t.Fatalf("poller not accepting commands after restart: %s", err)
+	}
+}
+
+func TestSupervisorRestartsPoller(t *testing.T) {
+	r, pins := newTestClient(t)
+
+	var hits int32
+	err := r.RegisterEdgeDetection(testPin, rpio.RiseEdge, func(rpio.Edge) {
+		atomic.AddInt32(&hits, 1)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	injectPanic(t, r, 1)
+
+	status := r.PollerStatus()
+	if !status.Healthy {
+		t.Errorf("got unhealthy poller after one restart, want healthy")
+	}
+	if status.LastRestart.IsZero() {
+		t.Errorf("got no last restart time, want one")
+	}
+
+	// Registrations survive the restart
+	pins.latch(testPin)
+	if err := r.Quiesce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Errorf("got %d callbacks after restart, want 1", got)
+	}
+
+	// Too many restarts in the window is unhealthy
+	for restarts := 2; restarts <= pollerMaxRestarts+1; restarts++ {
+		injectPanic(t, r, restarts)
+	}
+	status = r.PollerStatus()
+	if status.Restarts != pollerMaxRestarts+1 {
+		t.Errorf("got %d restarts, want %d", status.Restarts, pollerMaxRestarts+1)
+	}
+	if status.Healthy {
+		t.Errorf("got healthy poller after %d restarts, want unhealthy", status.Restarts)
+	}
+
+	if err := r.RemoveAndWait(testPin); err != nil {
+		t.Fatal(err)
+	}
+}
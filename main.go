@@ -1,27 +1,33 @@
 package main
 
 import (
+	"os"
+	"time"
+
+	"github.com/rytrose/soup-the-moon/cli"
 	"github.com/rytrose/soup-the-moon/game"
 	"github.com/rytrose/soup-the-moon/game/util"
 	"github.com/rytrose/soup-the-moon/io"
 )
 
-func init() {
-	if util.IsRasPi() {
-		// Open GPIO on init
-		io.RPIOClient.Start()
-	}
+func main() {
+	os.Exit(cli.Dispatch(&cli.Env{
+		Program:  os.Args[0],
+		Stdout:   os.Stdout,
+		Stderr:   os.Stderr,
+		Now:      time.Now,
+		RunGame:  runGame,
+		RPIOTest: io.TestRPIO,
+	}, os.Args[1:]))
 }
 
-func main() {
+// runGame runs the game.
+func runGame() {
 	if util.IsRasPi() {
-		// Close GPIO on exit
-		defer io.RPIOClient.Stop()
+		// Open GPIO, and close it on exit
+		io.Start()
+		defer io.Stop()
 	}
 
 	game.Run()
 }
-
-// func main() {
-// 	io.TestRPIO()
-// }